@@ -0,0 +1,185 @@
+package ebitencp
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// AnimationState is the state of a body that picks the animation of a SpriteAnimator.
+type AnimationState int
+
+const (
+	// Resting on the ground
+	AnimationIdle AnimationState = iota
+	// Moving along the ground
+	AnimationMoving
+	// Not touching the ground
+	AnimationAirborne
+	// Asleep in the space
+	AnimationSleeping
+)
+
+func (s AnimationState) String() string {
+	switch s {
+	case AnimationIdle:
+		return "idle"
+	case AnimationMoving:
+		return "moving"
+	case AnimationAirborne:
+		return "airborne"
+	case AnimationSleeping:
+		return "sleeping"
+	}
+	return "unknown"
+}
+
+// Animation is a sequence of frames shown one after another.
+type Animation struct {
+	Frames []*ebiten.Image
+	// FrameTime is how long each frame is shown in seconds.
+	FrameTime float64
+	// Loop starts over after the last frame. Otherwise the last frame stays.
+	Loop bool
+}
+
+// SheetFrames cuts count frames of w×h pixels from sheet, left to right from (x, y).
+func SheetFrames(sheet *ebiten.Image, x, y, w, h, count int) []*ebiten.Image {
+	frames := make([]*ebiten.Image, count)
+	for i := range frames {
+		frames[i] = sheet.SubImage(image.Rect(x+i*w, y, x+(i+1)*w, y+h)).(*ebiten.Image)
+	}
+	return frames
+}
+
+// frame returns the frame shown t seconds after the animation started.
+func (a *Animation) frame(t float64) *ebiten.Image {
+	if len(a.Frames) == 0 {
+		return nil
+	}
+	return a.Frames[a.frameIndex(t)]
+}
+
+// frameIndex returns the index of the frame shown t seconds after the animation started.
+// There must be at least one frame.
+func (a *Animation) frameIndex(t float64) int {
+	i := 0
+	if a.FrameTime > 0 {
+		i = int(t / a.FrameTime)
+	}
+	if a.Loop {
+		return i % len(a.Frames)
+	}
+	return min(i, len(a.Frames)-1)
+}
+
+// SpriteAnimator shows the frames of the animation for the state of the body of a sprite.
+// States without an animation fall back to AnimationIdle.
+//
+//	// In Update(), once per frame
+//	g.animator.Update(1 / 60.0)
+type SpriteAnimator struct {
+	Sprite     *Sprite
+	Animations map[AnimationState]*Animation
+	// MovingSpeed is the least speed across Up at which the body is moving.
+	MovingSpeed float64
+	// Up points away from the ground, {0, 1} by default. Use {0, -1} with Drawer.FlipYAxis.
+	Up cp.Vector
+	// GroundSlope is the cosine of the steepest contact normal that counts as ground.
+	GroundSlope float64
+	// Flip mirrors the sprite to face the direction it moves in along the X axis of the body.
+	// The frames are expected to face towards +X.
+	Flip bool
+
+	state  AnimationState
+	time   float64
+	facing float64
+}
+
+// NewSpriteAnimator animates sprite, which must be attached to a body.
+func NewSpriteAnimator(sprite *Sprite, animations map[AnimationState]*Animation) *SpriteAnimator {
+	return &SpriteAnimator{
+		Sprite:      sprite,
+		Animations:  animations,
+		MovingSpeed: 10,
+		Up:          cp.Vector{X: 0, Y: 1},
+		GroundSlope: 0.7,
+		Flip:        true,
+		facing:      1,
+	}
+}
+
+// State returns the state found by the last Update.
+func (a *SpriteAnimator) State() AnimationState {
+	return a.state
+}
+
+// Grounded reports whether body touches something below it,
+// that is a contact whose normal is within GroundSlope of Up.
+func (a *SpriteAnimator) Grounded(body *cp.Body) bool {
+	grounded := false
+	body.EachArbiter(func(arb *cp.Arbiter) {
+		shapeA, shapeB := arb.Shapes()
+		if arb.Count() == 0 || shapeA.Sensor() || shapeB.Sensor() {
+			return
+		}
+		// The normal points from body to the other one.
+		if arb.Normal().Neg().Dot(a.Up) >= a.GroundSlope {
+			grounded = true
+		}
+	})
+	return grounded
+}
+
+func (a *SpriteAnimator) findState(body *cp.Body) AnimationState {
+	if body.IsSleeping() {
+		return AnimationSleeping
+	}
+	if !a.Grounded(body) {
+		return AnimationAirborne
+	}
+	if a.across(body.Velocity()).Length() >= a.MovingSpeed {
+		return AnimationMoving
+	}
+	return AnimationIdle
+}
+
+// across returns v without its part along Up.
+func (a *SpriteAnimator) across(v cp.Vector) cp.Vector {
+	up := a.Up.Normalize()
+	return v.Sub(up.Mult(v.Dot(up)))
+}
+
+// Update advances the animation by dt seconds and sets the frame and facing of the sprite.
+func (a *SpriteAnimator) Update(dt float64) {
+	body := a.Sprite.Body()
+	if body == nil {
+		return
+	}
+	state := a.findState(body)
+	if state != a.state {
+		a.state, a.time = state, 0
+	} else {
+		a.time += dt
+	}
+	animation, ok := a.Animations[state]
+	if !ok {
+		animation = a.Animations[AnimationIdle]
+	}
+	if animation != nil {
+		if frame := animation.frame(a.time); frame != nil {
+			a.Sprite.Image = frame
+		}
+	}
+
+	if !a.Flip || state == AnimationSleeping {
+		return
+	}
+	// The flip mirrors the sprite along the X axis of the body, so the velocity is taken in body coordinates.
+	if v := body.Velocity().Unrotate(body.Rotation()).X; math.Abs(v) >= a.MovingSpeed {
+		a.facing = math.Copysign(1, v)
+	}
+	a.Sprite.Scale.X = math.Abs(a.Sprite.Scale.X) * a.facing
+}
@@ -0,0 +1,82 @@
+package ebitencp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+func TestAnimationFrameIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		frames    int
+		frameTime float64
+		loop      bool
+		t         float64
+		want      int
+	}{
+		{name: "start", frames: 4, frameTime: 0.1, loop: true, t: 0, want: 0},
+		{name: "within the first frame", frames: 4, frameTime: 0.1, loop: true, t: 0.09, want: 0},
+		{name: "second frame", frames: 4, frameTime: 0.1, loop: true, t: 0.15, want: 1},
+		{name: "loops", frames: 4, frameTime: 0.1, loop: true, t: 0.45, want: 0},
+		{name: "loops twice", frames: 4, frameTime: 0.1, loop: true, t: 0.95, want: 1},
+		{name: "stays on the last frame", frames: 4, frameTime: 0.1, loop: false, t: 10, want: 3},
+		{name: "no frame time", frames: 4, frameTime: 0, loop: true, t: 10, want: 0},
+		{name: "one frame", frames: 1, frameTime: 0.1, loop: true, t: 0.35, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Animation{
+				Frames:    make([]*ebiten.Image, tt.frames),
+				FrameTime: tt.frameTime,
+				Loop:      tt.loop,
+			}
+			if got := a.frameIndex(tt.t); got != tt.want {
+				t.Errorf("frameIndex(%g) = %d, want %d", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnimationFrameEmpty(t *testing.T) {
+	a := &Animation{FrameTime: 0.1, Loop: true}
+	if got := a.frame(1); got != nil {
+		t.Errorf("frame() = %v, want nil", got)
+	}
+}
+
+func TestSpriteAnimatorFacing(t *testing.T) {
+	tests := []struct {
+		name     string
+		angle    float64
+		velocity cp.Vector
+		want     float64
+	}{
+		{name: "right", angle: 0, velocity: cp.Vector{X: 20}, want: 1},
+		{name: "left", angle: 0, velocity: cp.Vector{X: -20}, want: -1},
+		{name: "too slow", angle: 0, velocity: cp.Vector{X: -5}, want: 1},
+		{name: "upside down moving right", angle: math.Pi, velocity: cp.Vector{X: 20}, want: -1},
+		{name: "upside down moving left", angle: math.Pi, velocity: cp.Vector{X: -20}, want: 1},
+		{name: "on a wall moving up", angle: math.Pi / 2, velocity: cp.Vector{Y: 20}, want: 1},
+		{name: "on a wall moving right", angle: math.Pi / 2, velocity: cp.Vector{X: 20}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			space := cp.NewSpace()
+			body := addTestBox(space, cp.Vector{})
+			body.SetAngle(tt.angle)
+			body.SetVelocityVector(tt.velocity)
+			sprite := NewSpriteLayer().AttachBody(body, NewSprite(nil))
+			a := NewSpriteAnimator(sprite, nil)
+			a.Update(1 / 60.0)
+			if a.State() != AnimationAirborne {
+				t.Errorf("State() = %v, want %v", a.State(), AnimationAirborne)
+			}
+			if got := sprite.Scale.X; got != tt.want {
+				t.Errorf("Scale.X = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
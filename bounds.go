@@ -0,0 +1,125 @@
+package ebitencp
+
+import (
+	"image/color"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// BBColoring chooses the colors of the boxes drawn by BBOverlay.
+type BBColoring int
+
+const (
+	// Dynamic, kinematic and static bodies get their Theme color.
+	BBColorByBodyType BBColoring = iota
+	// Boxes get the color of their shape, which shows awake, idle and sleeping bodies.
+	BBColorBySleep
+)
+
+// BBOverlay draws the bounding boxes cp uses for the broadphase.
+// Set it to Drawer.BoundingBoxes.
+//
+// The leaves of the spatial index are the shape boxes grown in the direction
+// the body moves, so fast bodies have larger leaves than their shape boxes.
+// The trees are not drawn for spaces using a spatial hash.
+//
+// Shape boxes are drawn at the transforms blended by Alpha,
+// and the tree nodes where the index holds them after the last step.
+type BBOverlay struct {
+	// Shapes draws the BB of each shape.
+	Shapes bool
+	// DynamicTree and StaticTree draw the nodes of the spatial indexes.
+	DynamicTree, StaticTree bool
+	ColorBy                 BBColoring
+}
+
+func NewBBOverlay() *BBOverlay {
+	return &BBOverlay{
+		Shapes:      true,
+		DynamicTree: true,
+		StaticTree:  true,
+		ColorBy:     BBColorByBodyType,
+	}
+}
+
+func (d *Drawer) drawBoundingBoxes(space *cp.Space) {
+	o := d.BoundingBoxes
+	if o.Shapes {
+		space.EachShape(func(shape *cp.Shape) {
+			d.drawBB(d.shapeBB(shape), d.bbColor(shape))
+		})
+	}
+	if o.DynamicTree {
+		d.drawTree(spaceShapeIndex(space, false))
+	}
+	if o.StaticTree {
+		d.drawTree(spaceShapeIndex(space, true))
+	}
+}
+
+// shapeBB returns the BB of shape at the transform its body is drawn with.
+func (d *Drawer) shapeBB(shape *cp.Shape) cp.BB {
+	if _, ok := d.previous[shape.Body()]; !ok || d.Alpha >= 1 {
+		return shape.BB()
+	}
+	t, _ := d.bodyTransform(shape.Body())
+	var points []cp.Vector
+	var r float64
+	switch class := shape.Class.(type) {
+	case *cp.Circle:
+		points, r = []cp.Vector{t.Point(circleOffset(class))}, class.Radius()
+	case *cp.Segment:
+		points, r = []cp.Vector{t.Point(class.A()), t.Point(class.B())}, class.Radius()
+	case *cp.PolyShape:
+		points, r = make([]cp.Vector, class.Count()), class.Radius()
+		for i := range points {
+			points[i] = t.Point(class.Vert(i))
+		}
+	default:
+		return shape.BB()
+	}
+	bb := cp.NewBBForCircle(points[0], r)
+	for _, p := range points[1:] {
+		bb = bb.Expand(p.Sub(cp.Vector{X: r, Y: r})).Expand(p.Add(cp.Vector{X: r, Y: r}))
+	}
+	return bb
+}
+
+func (d *Drawer) drawTree(index *cp.SpatialIndex) {
+	if _, ok := spatialIndexer(index).(*cp.BBTree); !ok {
+		return
+	}
+	var walk func(node *cp.Node)
+	walk = func(node *cp.Node) {
+		if node == nil {
+			return
+		}
+		bb, shape, a, b := treeNode(node)
+		if shape != nil {
+			d.drawBB(bb, d.bbColor(shape))
+			return
+		}
+		d.drawBB(bb, toFColor(d.Theme.BBTree))
+		walk(a)
+		walk(b)
+	}
+	walk(index.GetRootIfTree())
+}
+
+func (d *Drawer) bbColor(shape *cp.Shape) cp.FColor {
+	if d.BoundingBoxes.ColorBy == BBColorBySleep {
+		c := d.ShapeColor(shape, nil)
+		c.A = 1
+		return c
+	}
+	var c color.RGBA
+	switch shape.Body().GetType() {
+	case cp.BODY_DYNAMIC:
+		c = d.Theme.BBDynamic
+	case cp.BODY_KINEMATIC:
+		c = d.Theme.BBKinematic
+	case cp.BODY_STATIC:
+		c = d.Theme.BBStatic
+	}
+	return toFColor(c)
+}
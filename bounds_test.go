@@ -0,0 +1,41 @@
+package ebitencp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+func TestShapeBB(t *testing.T) {
+	space := cp.NewSpace()
+	body := addTestBox(space, cp.Vector{})
+	space.AddShape(cp.NewCircle(body, 1, cp.Vector{X: 2, Y: 0}))
+	space.AddShape(cp.NewSegment(body, cp.Vector{X: -3, Y: 0}, cp.Vector{X: 3, Y: 0}, 0.5))
+	before := map[*cp.Shape]cp.BB{}
+	body.EachShape(func(shape *cp.Shape) { before[shape] = shape.CacheBB() })
+
+	d := &Drawer{}
+	d.SaveTransforms(space)
+	body.SetPosition(cp.Vector{X: 10, Y: 0})
+	body.SetAngle(1)
+	body.EachShape(func(shape *cp.Shape) { shape.CacheBB() })
+
+	body.EachShape(func(shape *cp.Shape) {
+		d.Alpha = 1
+		if got, want := d.shapeBB(shape), shape.BB(); got != want {
+			t.Errorf("Alpha 1: BB = %v, want %v", got, want)
+		}
+		// At Alpha 0 the boxes are where the shapes were before the move.
+		d.Alpha = 0
+		if got, want := d.shapeBB(shape), before[shape]; !bbNear(got, want) {
+			t.Errorf("Alpha 0: BB = %v, want %v", got, want)
+		}
+	})
+}
+
+func bbNear(a, b cp.BB) bool {
+	const epsilon = 1e-9
+	return math.Abs(a.L-b.L) < epsilon && math.Abs(a.B-b.B) < epsilon &&
+		math.Abs(a.R-b.R) < epsilon && math.Abs(a.T-b.T) < epsilon
+}
@@ -0,0 +1,57 @@
+package ebitencp
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// DefaultBreakThreshold breaks a constraint at 90% of its MaxForce.
+const DefaultBreakThreshold = 0.9
+
+// ConstraintStress returns the force a constraint applied in the last step of dt
+// relative to its MaxForce. It is 0 for constraints without a finite MaxForce.
+func ConstraintStress(c *cp.Constraint, dt float64) float64 {
+	maxForce := c.MaxForce()
+	if dt <= 0 || maxForce <= 0 || math.IsInf(maxForce, 1) {
+		return 0
+	}
+	// Convert the impulse to a force by dividing it by the timestep.
+	return c.Class.GetImpulse() / dt / maxForce
+}
+
+// MakeBreakable removes c from its space after a step in which its
+// ConstraintStress exceeds threshold, such as DefaultBreakThreshold.
+// onBreak is called after the removal when it is not nil.
+//
+// The helper is set as c.PostSolve, which snapshots do not keep.
+// Call it again after restoring one.
+func MakeBreakable(c *cp.Constraint, threshold float64, onBreak func(space *cp.Space, c *cp.Constraint)) {
+	c.PostSolve = func(c *cp.Constraint, space *cp.Space) {
+		if ConstraintStress(c, space.TimeStep()) <= threshold {
+			return
+		}
+		space.AddPostStepCallback(func(space *cp.Space, key, _ interface{}) {
+			c := key.(*cp.Constraint)
+			if !space.ContainsConstraint(c) {
+				return
+			}
+			space.RemoveConstraint(c)
+			if onBreak != nil {
+				onBreak(space, c)
+			}
+		}, c, nil)
+	}
+}
+
+// stressColor returns the color of c between Theme.StressLow and Theme.StressHigh.
+func (d *Drawer) stressColor(c *cp.Constraint, dt float64) cp.FColor {
+	t := float32(cp.Clamp01(ConstraintStress(c, dt)))
+	low, high := toFColor(d.Theme.StressLow), toFColor(d.Theme.StressHigh)
+	return cp.FColor{
+		R: low.R + (high.R-low.R)*t,
+		G: low.G + (high.G-low.G)*t,
+		B: low.B + (high.B-low.B)*t,
+		A: low.A + (high.A-low.A)*t,
+	}
+}
@@ -0,0 +1,82 @@
+package ebitencp
+
+import (
+	"github.com/jakecoffman/cp/v2"
+)
+
+// ContactDetail is a set of contact details drawn by ContactOverlay.
+type ContactDetail uint
+
+const (
+	// Normal as an arrow from each contact point
+	ContactNormal ContactDetail = 1 << iota
+	// Penetration as a line between the surface points of the two shapes
+	ContactDepth
+	// Normal impulse of the solver as an arrow
+	ContactNormalImpulse
+	// Tangent (friction) impulse of the solver as an arrow
+	ContactTangentImpulse
+	// Contact points colored by whether they are new this step or persistent
+	ContactAge
+
+	ContactAll = ContactNormal | ContactDepth | ContactNormalImpulse | ContactTangentImpulse | ContactAge
+)
+
+// ContactOverlay draws the contacts of the last step in detail.
+// Set it to Drawer.Contacts, where it replaces the plain collision points.
+//
+// Contact points move with their bodies to the transforms blended by Alpha.
+// Normals and impulses point from the first shape of an arbiter to the second,
+// so the impulse arrows show what the second body receives.
+type ContactOverlay struct {
+	Show ContactDetail
+	// NormalLength is the length of the normal arrows in pixels.
+	NormalLength float64
+	// ImpulseScale is the arrow length per unit of impulse.
+	ImpulseScale float64
+}
+
+func NewContactOverlay() *ContactOverlay {
+	return &ContactOverlay{
+		Show:         ContactAll,
+		NormalLength: 10,
+		ImpulseScale: 2,
+	}
+}
+
+func (d *Drawer) drawContacts(space *cp.Space) {
+	c := d.Contacts
+	point := toFColor(d.Theme.CollisionPoint)
+	for _, arb := range spaceArbiters(space) {
+		set := arb.ContactPointSet()
+		impulses := arbiterImpulses(arb)
+		if c.Show&ContactAge != 0 {
+			point = toFColor(d.Theme.ContactPersistent)
+			if arb.IsFirstContact() {
+				point = toFColor(d.Theme.ContactNew)
+			}
+		}
+		bodyA, bodyB := arb.Bodies()
+		// The normal turns with the first body.
+		t, _ := d.bodyTransform(bodyA)
+		n := t.Vect(set.Normal.Unrotate(bodyA.Rotation()))
+		for i := 0; i < set.Count; i++ {
+			a := d.blendedPoint(bodyA, set.Points[i].PointA)
+			b := d.blendedPoint(bodyB, set.Points[i].PointB)
+			p := a.Lerp(b, 0.5)
+			if c.Show&ContactDepth != 0 {
+				d.DrawSegment(a, b, toFColor(d.Theme.ContactDepth), nil)
+			}
+			if c.Show&ContactNormal != 0 {
+				d.drawArrow(p, p.Add(n.Mult(d.pixels(c.NormalLength))), toFColor(d.Theme.ContactNormal))
+			}
+			if c.Show&ContactNormalImpulse != 0 {
+				d.drawArrow(p, p.Add(n.Mult(impulses[i].X*c.ImpulseScale)), toFColor(d.Theme.NormalImpulse))
+			}
+			if c.Show&ContactTangentImpulse != 0 {
+				d.drawArrow(p, p.Add(n.Perp().Mult(impulses[i].Y*c.ImpulseScale)), toFColor(d.Theme.TangentImpulse))
+			}
+			d.DrawDot(4, p, point, nil)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package ebitencp
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// SimulationControl pauses, single-steps, slows down and resets a space.
+//
+//	func (g *Game) Update() error {
+//		if err := g.control.HandleKeys(); err != nil {
+//			return err
+//		}
+//		if err := g.control.HandleMouseEvent(); err != nil {
+//			return err
+//		}
+//		g.control.Step(1 / 60.0)
+//		return nil
+//	}
+type SimulationControl struct {
+	Paused bool
+	// TimeScale is the speed of the simulation. 0.25 runs at 1/4 speed.
+	// Steps are skipped rather than shortened, so the physics is the same at any speed.
+	TimeScale float64
+	// History records every step when it is not nil.
+	// The left and right keys and its timeline scrub through it.
+	History *History
+	// Restored is called after Reset replaces the bodies, shapes and
+	// constraints of the space. Callbacks such as Constraint.PostSolve are
+	// not part of a snapshot and can be set again here.
+	// Seeking through the History keeps the same bodies and does not call it.
+	Restored func(space *cp.Space)
+
+	drawer       *Drawer
+	space        *cp.Space
+	initial      *Snapshot
+	stepRequests int
+	accumulator  float64
+}
+
+// NewSimulationControl controls space, which is dragged through d.
+// The current state of space is what Reset goes back to.
+// Any body dragged by d is released first.
+func NewSimulationControl(d *Drawer, space *cp.Space) (*SimulationControl, error) {
+	d.handler.reset()
+	initial, err := TakeSnapshot(space)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulationControl{
+		TimeScale: 1,
+		drawer:    d,
+		space:     space,
+		initial:   initial,
+	}, nil
+}
+
+func (c *SimulationControl) TogglePause() {
+	c.Paused = !c.Paused
+	c.accumulator = 0
+}
+
+// StepOnce takes a single step on the next Step while paused.
+func (c *SimulationControl) StepOnce() {
+	c.stepRequests++
+}
+
+// Reset restores the state the control was created with.
+func (c *SimulationControl) Reset() error {
+	c.drawer.handler.reset()
+	c.stepRequests = 0
+	c.accumulator = 0
+	if err := c.initial.RestoreInto(c.space); err != nil {
+		return err
+	}
+	c.restored()
+	if c.History != nil {
+		c.History.Clear()
+	}
+	return nil
+}
+
+// Seek pauses and restores the state at index i of the History.
+func (c *SimulationControl) Seek(i int) error {
+	if c.History == nil {
+		return fmt.Errorf("ebitencp: seeking without a history")
+	}
+	if !c.Paused {
+		c.TogglePause()
+	}
+	c.stepRequests = 0
+	c.History.Seek(i)
+	return nil
+}
+
+func (c *SimulationControl) restored() {
+	if c.Restored != nil {
+		c.Restored(c.space)
+	}
+}
+
+// HandleMouseEvent is Drawer.HandleMouseEvent.
+// While paused, the grabbed body is moved to the pointer.
+// Dragging the timeline of the History seeks instead.
+func (c *SimulationControl) HandleMouseEvent() error {
+	if c.History != nil {
+		if i, ok := c.History.handleTimeline(); ok {
+			if c.Paused && i == c.History.Position() {
+				return nil
+			}
+			return c.Seek(i)
+		}
+	}
+	c.drawer.HandleMouseEvent(c.space)
+	if c.Paused {
+		c.drawer.handler.dragWhilePaused(c.space)
+	}
+	return nil
+}
+
+// HandleKeys applies the default keys:
+// P pauses and resumes, N steps once while paused,
+// [ and ] halve and double TimeScale, and R resets.
+// With a History, the left and right keys step back and forward through it.
+func (c *SimulationControl) HandleKeys() error {
+	if c.History != nil {
+		if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+			if err := c.Seek(c.History.Position() - 1); err != nil {
+				return err
+			}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+			if err := c.Seek(c.History.Position() + 1); err != nil {
+				return err
+			}
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		c.TogglePause()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) && c.Paused {
+		c.StepOnce()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) && c.TimeScale > 1/64.0 {
+		c.TimeScale /= 2
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) && c.TimeScale < 1 {
+		c.TimeScale *= 2
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		return c.Reset()
+	}
+	return nil
+}
+
+// Step steps the space by dt as the controls allow and returns the number of steps taken.
+func (c *SimulationControl) Step(dt float64) int {
+	n := c.steps(dt, dt)
+	for i := 0; i < n; i++ {
+		c.space.Step(dt)
+		c.record()
+	}
+	return n
+}
+
+// record saves a step in the History.
+func (c *SimulationControl) record() {
+	if c.History != nil {
+		c.History.Record()
+	}
+}
+
+// steps returns how many steps of dt to take for a frame of frameTime.
+func (c *SimulationControl) steps(frameTime, dt float64) int {
+	if c.Paused {
+		if c.stepRequests == 0 {
+			return 0
+		}
+		c.stepRequests--
+		return 1
+	}
+	c.accumulator += frameTime * c.TimeScale
+	n := 0
+	for c.accumulator >= dt {
+		c.accumulator -= dt
+		n++
+	}
+	return n
+}
+
+func (c *SimulationControl) String() string {
+	if c.Paused && c.History != nil {
+		return fmt.Sprintf("Paused at %d/%d (P: resume, N: step, Left/Right: scrub, R: reset)", c.History.Position()+1, c.History.Len())
+	}
+	if c.Paused {
+		return "Paused (P: resume, N: step, R: reset)"
+	}
+	return fmt.Sprintf("Running x%g (P: pause, [ ]: speed, R: reset)", c.TimeScale)
+}
@@ -0,0 +1,115 @@
+package ebitencp
+
+import (
+	"image/color"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+type debugKind int
+
+const (
+	debugLine debugKind = iota
+	debugArrow
+	debugCircle
+	debugPolygon
+	debugPoint
+	debugText
+)
+
+// DebugShape is a shape queued by the immediate-mode methods of Drawer,
+// such as Line and Text. Set Frames to keep it on screen for longer.
+//
+//	g.drawer.Arrow(from, to, color.White).Frames = 30
+type DebugShape struct {
+	// Frames is the number of times the shape is drawn by DrawDebug. It is 1 by default.
+	Frames int
+
+	kind   debugKind
+	points []cp.Vector
+	radius float64
+	text   string
+	color  color.Color
+}
+
+func (d *Drawer) queueDebug(s *DebugShape) *DebugShape {
+	s.Frames = 1
+	d.debugShapes = append(d.debugShapes, s)
+	return s
+}
+
+// Line queues a line from a to b in world coordinates.
+func (d *Drawer) Line(a, b cp.Vector, clr color.Color) *DebugShape {
+	return d.queueDebug(&DebugShape{kind: debugLine, points: []cp.Vector{a, b}, color: clr})
+}
+
+// Arrow queues an arrow from a to b in world coordinates.
+func (d *Drawer) Arrow(a, b cp.Vector, clr color.Color) *DebugShape {
+	return d.queueDebug(&DebugShape{kind: debugArrow, points: []cp.Vector{a, b}, color: clr})
+}
+
+// Circle queues the outline of a circle in world coordinates.
+func (d *Drawer) Circle(center cp.Vector, radius float64, clr color.Color) *DebugShape {
+	return d.queueDebug(&DebugShape{kind: debugCircle, points: []cp.Vector{center}, radius: radius, color: clr})
+}
+
+// Rect queues the outline of bb.
+func (d *Drawer) Rect(bb cp.BB, clr color.Color) *DebugShape {
+	verts := []cp.Vector{{X: bb.L, Y: bb.B}, {X: bb.R, Y: bb.B}, {X: bb.R, Y: bb.T}, {X: bb.L, Y: bb.T}}
+	return d.queueDebug(&DebugShape{kind: debugPolygon, points: verts, color: clr})
+}
+
+// Polygon queues the closed outline through verts in world coordinates.
+func (d *Drawer) Polygon(verts []cp.Vector, clr color.Color) *DebugShape {
+	return d.queueDebug(&DebugShape{kind: debugPolygon, points: append([]cp.Vector(nil), verts...), color: clr})
+}
+
+// Point queues a dot at p in world coordinates.
+func (d *Drawer) Point(p cp.Vector, clr color.Color) *DebugShape {
+	return d.queueDebug(&DebugShape{kind: debugPoint, points: []cp.Vector{p}, color: clr})
+}
+
+// Text queues str with its top left corner at p in world coordinates.
+// It is drawn with DefaultFace at the same size at any zoom.
+func (d *Drawer) Text(p cp.Vector, str string, clr color.Color) *DebugShape {
+	return d.queueDebug(&DebugShape{kind: debugText, points: []cp.Vector{p}, text: str, color: clr})
+}
+
+// DrawDebug draws the queued debug shapes and drops those whose Frames run out.
+// DrawSpace calls it last; call it once per frame when drawing otherwise.
+func (d *Drawer) DrawDebug() {
+	kept := d.debugShapes[:0]
+	for _, s := range d.debugShapes {
+		d.drawDebugShape(s)
+		s.Frames--
+		if s.Frames > 0 {
+			kept = append(kept, s)
+		}
+	}
+	clear(d.debugShapes[len(kept):])
+	d.debugShapes = kept
+}
+
+func (d *Drawer) drawDebugShape(s *DebugShape) {
+	clr := colorToFColor(s.color)
+	switch s.kind {
+	case debugLine:
+		d.DrawSegment(s.points[0], s.points[1], clr, nil)
+	case debugArrow:
+		d.drawArrow(s.points[0], s.points[1], clr)
+	case debugCircle:
+		d.drawRing(s.points[0], s.radius, clr)
+	case debugPolygon:
+		path := d.newPath()
+		for _, p := range s.points {
+			path.LineTo(p)
+		}
+		path.Close()
+		d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+	case debugPoint:
+		d.DrawDot(4, s.points[0], clr, nil)
+	case debugText:
+		p := d.WorldToScreen(s.points[0])
+		drawText(d.Screen, s.text, DefaultFace(), p.X, p.Y, s.color)
+	}
+}
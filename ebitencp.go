@@ -1,6 +1,7 @@
 package ebitencp
 
 import (
+	"image"
 	"image/color"
 	"math"
 
@@ -13,11 +14,24 @@ import (
 const DrawPointLineScale = 1.0
 
 type Drawer struct {
-	Screen       *ebiten.Image
+	Screen *ebiten.Image
+	// Viewport is the part of the screen the space is drawn into, centered on the camera.
+	// When it is empty, the bounds of Screen are used, so drawing into a sub-image
+	// of the screen draws into that part of it.
+	// When it is set, WithScreen draws into the sub-image of Viewport,
+	// so drawers with their own cameras can split one screen.
+	Viewport image.Rectangle
+	// ScreenWidth and ScreenHeight are the viewport until a Screen is set.
 	ScreenWidth  int
 	ScreenHeight int
-	StrokeWidth  float32
-	FlipYAxis    bool
+	// StrokeWidth is the width of outlines in pixels.
+	StrokeWidth float32
+	FlipYAxis   bool
+	// PixelsPerUnit is how many pixels one world unit spans with an identity GeoM.
+	// GeoM is applied first, in world units, and its zoom multiplies this scale.
+	// It lets spaces be built in meters and still be drawn at a readable size.
+	// Outlines, dots, markers and the grab radius stay the same size in pixels.
+	PixelsPerUnit float64
 	// Drawing colors
 	Theme *Theme
 	// GeoM for drawing vertices. Useful for cameras.
@@ -26,6 +40,35 @@ type Drawer struct {
 	OptStroke *ebiten.DrawTrianglesOptions
 	OptFill   *ebiten.DrawTrianglesOptions
 
+	// Alpha blends the transforms saved by SaveTransforms (0)
+	// with the current ones (1) in DrawSpace. It is 1 by default, so saving
+	// transforms for the motion or trail overlays alone does not draw bodies a step behind.
+	Alpha float64
+	// Motion is drawn over the space by DrawSpace when it is not nil.
+	Motion *MotionOverlay
+	// Contacts replaces the collision points drawn by DrawSpace when it is not nil.
+	Contacts *ContactOverlay
+	// BoundingBoxes is drawn over the space by DrawSpace when it is not nil.
+	BoundingBoxes *BBOverlay
+	// Islands colors shapes by island in DrawSpace when it is not nil.
+	Islands *IslandOverlay
+	// Heatmap colors the shapes of dynamic bodies when it is not nil.
+	// It takes precedence over Islands.
+	Heatmap *Heatmap
+	// Grid is drawn under the space by DrawSpace when it is not nil.
+	Grid *GridOverlay
+	// Trails is drawn under the space by DrawSpace when it is not nil.
+	Trails *TrailOverlay
+	// Textures fills shapes with images instead of solid colors when it is not nil.
+	Textures *TextureFills
+	// Sprites is drawn instead of the shapes by DrawSpace when it is not nil.
+	Sprites *SpriteLayer
+	// Labels is drawn over the space by DrawSpace when it is not nil.
+	Labels *LabelOverlay
+	// StressColors colors constraints by ConstraintStress in DrawSpace,
+	// from Theme.StressLow to Theme.StressHigh.
+	StressColors bool
+
 	// Deprecated: Use GeoM instead of Camera
 	Camera Camera
 	// Deprecated: Use OptStroke and OptFill instead of AntiAlias
@@ -33,6 +76,19 @@ type Drawer struct {
 
 	handler    mouseEventHandler
 	whiteImage *ebiten.Image
+	previous   map[*cp.Body]bodyTransform
+	drawStats  DrawStats
+	islands    map[*cp.Body]island
+	// Queued by Line, Arrow and the other immediate-mode methods
+	debugShapes []*DebugShape
+	// The texture of the shape being drawn
+	texturing *texturing
+}
+
+// DrawStats counts what the drawer has drawn.
+type DrawStats struct {
+	Triangles int
+	DrawCalls int
 }
 
 type Camera struct {
@@ -44,14 +100,16 @@ func NewDrawer(screenWidth, screenHeight int) *Drawer {
 	whiteImage.Fill(color.White)
 	antiAlias := true
 	return &Drawer{
-		whiteImage:   whiteImage,
-		ScreenWidth:  screenWidth,
-		ScreenHeight: screenHeight,
-		AntiAlias:    antiAlias,
-		StrokeWidth:  1,
-		FlipYAxis:    false,
-		Theme:        DefaultTheme(),
-		GeoM:         &ebiten.GeoM{},
+		whiteImage:    whiteImage,
+		ScreenWidth:   screenWidth,
+		ScreenHeight:  screenHeight,
+		AntiAlias:     antiAlias,
+		StrokeWidth:   1,
+		FlipYAxis:     false,
+		PixelsPerUnit: 1,
+		Alpha:         1,
+		Theme:         DefaultTheme(),
+		GeoM:          &ebiten.GeoM{},
 		Camera: Camera{
 			Offset: cp.Vector{X: 0, Y: 0},
 		},
@@ -67,57 +125,112 @@ func NewDrawer(screenWidth, screenHeight int) *Drawer {
 }
 
 func (d *Drawer) WithScreen(screen *ebiten.Image) *Drawer {
+	if !d.Viewport.Empty() {
+		screen = screen.SubImage(d.Viewport).(*ebiten.Image)
+	}
 	d.Screen = screen
 	return d
 }
 
+// viewport returns the rectangle of the screen the camera is centered in, in screen coordinates.
+func (d *Drawer) viewport() image.Rectangle {
+	if !d.Viewport.Empty() {
+		return d.Viewport
+	}
+	if d.Screen != nil {
+		return d.Screen.Bounds()
+	}
+	return image.Rect(0, 0, d.ScreenWidth, d.ScreenHeight)
+}
+
+// DrawSpace draws space like cp.DrawSpace.
+// Shapes and constraints are drawn at the transforms blended by Alpha,
+// and each joint type is drawn in its own way and Theme color.
+func (d *Drawer) DrawSpace(space *cp.Space) {
+	if d.Islands != nil {
+		d.findIslands(space)
+		defer clear(d.islands)
+	}
+	if d.Grid != nil {
+		d.drawGrid()
+	}
+	if d.Trails != nil {
+		d.drawTrails()
+	}
+	if d.Sprites != nil {
+		d.DrawSprites()
+		if d.Sprites.Shapes {
+			space.EachShape(func(shape *cp.Shape) {
+				d.drawShapeOutline(shape)
+			})
+		}
+	} else {
+		space.EachShape(func(shape *cp.Shape) {
+			d.drawShape(shape)
+		})
+	}
+	space.EachConstraint(func(constraint *cp.Constraint) {
+		d.drawConstraint(constraint, space.TimeStep())
+	})
+	if d.Contacts != nil {
+		d.drawContacts(space)
+	} else {
+		d.drawCollisionPoints(space)
+	}
+	if d.Islands != nil && d.Islands.Links {
+		d.drawIslandLinks()
+	}
+	if d.BoundingBoxes != nil {
+		d.drawBoundingBoxes(space)
+	}
+	if d.Motion != nil {
+		d.drawMotion(space)
+	}
+	if d.Labels != nil {
+		d.drawLabels(space)
+	}
+	d.DrawDebug()
+}
+
+func (d *Drawer) drawCollisionPoints(space *cp.Space) {
+	color := d.CollisionPointColor()
+	for _, arb := range spaceArbiters(space) {
+		set := arb.ContactPointSet()
+		for i := 0; i < set.Count; i++ {
+			a := set.Points[i].PointA.Add(set.Normal.Mult(-d.pixels(2)))
+			b := set.Points[i].PointB.Add(set.Normal.Mult(d.pixels(2)))
+			d.DrawSegment(a, b, color, nil)
+		}
+	}
+}
+
 func (d *Drawer) DrawCircle(pos cp.Vector, angle, radius float64, outline, fill cp.FColor, data interface{}) {
 
-	path := &vector.Path{}
-	path.Arc(
-		float32(pos.X),
-		float32(pos.Y),
-		float32(radius),
-		0, 2*math.Pi, vector.Clockwise)
-	d.drawFill(d.Screen, *path, fill.R, fill.G, fill.B, fill.A)
-
-	path.MoveTo(
-		float32(pos.X),
-		float32(pos.Y))
-	path.LineTo(
-		float32(pos.X+math.Cos(angle)*radius),
-		float32(pos.Y+math.Sin(angle)*radius))
+	path := d.newPath()
+	path.Arc(pos, radius, 0, 2*math.Pi, vector.Clockwise)
+	d.drawFill(d.Screen, path, fill.R, fill.G, fill.B, fill.A)
+
+	path.MoveTo(pos)
+	path.LineTo(pos.Add(cp.ForAngle(angle).Mult(radius)))
 	path.Close()
 
-	d.drawOutline(d.Screen, *path, outline.R, outline.G, outline.B, outline.A)
+	d.drawOutline(d.Screen, path, outline.R, outline.G, outline.B, outline.A)
 }
 
 func (d *Drawer) DrawSegment(a, b cp.Vector, fill cp.FColor, data interface{}) {
-	var path *vector.Path = &vector.Path{}
-	path.MoveTo(
-		float32(a.X),
-		float32(a.Y))
-	path.LineTo(
-		float32(b.X),
-		float32(b.Y))
+	path := d.newPath()
+	path.MoveTo(a)
+	path.LineTo(b)
 	path.Close()
-	d.drawOutline(d.Screen, *path, fill.R, fill.G, fill.B, fill.A)
+	d.drawOutline(d.Screen, path, fill.R, fill.G, fill.B, fill.A)
 }
 
 func (d *Drawer) DrawFatSegment(a, b cp.Vector, radius float64, outline, fill cp.FColor, data interface{}) {
-	var path vector.Path = vector.Path{}
-	t1 := float32(math.Atan2(b.Y-a.Y, b.X-a.X)) + math.Pi/2
+	path := d.newPath()
+	t1 := math.Atan2(b.Y-a.Y, b.X-a.X) + math.Pi/2
 	t2 := t1 + math.Pi
-	path.Arc(
-		float32(a.X),
-		float32(a.Y),
-		float32(radius),
-		t1, t1+math.Pi, vector.Clockwise)
-	path.Arc(
-		float32(b.X),
-		float32(b.Y),
-		float32(radius),
-		t2, t2+math.Pi, vector.Clockwise)
+	path.Arc(a, radius, t1, t1+math.Pi, vector.Clockwise)
+	path.Arc(b, radius, t2, t2+math.Pi, vector.Clockwise)
 	path.Close()
 	d.drawFill(d.Screen, path, fill.R, fill.G, fill.B, fill.A)
 	d.drawOutline(d.Screen, path, outline.R, outline.G, outline.B, outline.A)
@@ -141,10 +254,11 @@ func (d *Drawer) DrawPolygon(count int, verts []cp.Vector, radius float64, outli
 		extrude[i] = ExtrudeVerts{offset, n2}
 	}
 
-	path := vector.Path{}
-	inset := -math.Max(0, 1.0/DrawPointLineScale-radius)
-	outset := 1.0/DrawPointLineScale + radius - inset
-	outset2 := 1.0/DrawPointLineScale + radius - inset
+	path := d.newPath()
+	lineScale := DrawPointLineScale * d.ppu()
+	inset := -math.Max(0, 1.0/lineScale-radius)
+	outset := 1.0/lineScale + radius - inset
+	outset2 := 1.0/lineScale + radius - inset
 	j := count - 1
 	for i := 0; i < count; {
 		vA := verts[i]
@@ -165,25 +279,13 @@ func (d *Drawer) DrawPolygon(count int, verts []cp.Vector, radius float64, outli
 		outer3 := innerA.Add(offsetA.Mult(outset2))
 		outer4 := innerA.Add(nA.Mult(outset))
 
-		path.LineTo(
-			float32(outer1.X),
-			float32(outer1.Y))
-		path.LineTo(
-			float32(outer0.X),
-			float32(outer0.Y))
+		path.LineTo(outer1)
+		path.LineTo(outer0)
 		if radius != 0 {
-			path.ArcTo(
-				float32(outer3.X),
-				float32(outer3.Y),
-				float32(outer4.X),
-				float32(outer4.Y),
-				float32(radius),
-			)
+			path.ArcTo(outer3, outer4, radius)
 		} else {
 			// ArcTo() and Arc() are very computationally expensive, so use LineTo()
-			path.LineTo(
-				float32(outer2.X),
-				float32(outer2.Y))
+			path.LineTo(outer2)
 		}
 
 		j = i
@@ -194,16 +296,14 @@ func (d *Drawer) DrawPolygon(count int, verts []cp.Vector, radius float64, outli
 	d.drawFill(d.Screen, path, fill.R, fill.G, fill.B, fill.A)
 	d.drawOutline(d.Screen, path, outline.R, outline.G, outline.B, outline.A)
 }
+
+// DrawDot draws a dot of size pixels across, as cp's debug drawing passes it.
 func (d *Drawer) DrawDot(size float64, pos cp.Vector, fill cp.FColor, data interface{}) {
-	var path *vector.Path = &vector.Path{}
-	path.Arc(
-		float32(pos.X),
-		float32(pos.Y),
-		float32(2),
-		0, 2*math.Pi, vector.Clockwise)
+	path := d.newPath()
+	path.Arc(pos, d.pixels(size/2), 0, 2*math.Pi, vector.Clockwise)
 	path.Close()
 
-	d.drawFill(d.Screen, *path, fill.R, fill.G, fill.B, fill.A)
+	d.drawFill(d.Screen, path, fill.R, fill.G, fill.B, fill.A)
 }
 
 func (d *Drawer) Flags() uint {
@@ -216,6 +316,12 @@ func (d *Drawer) OutlineColor() cp.FColor {
 
 func (d *Drawer) ShapeColor(shape *cp.Shape, data interface{}) cp.FColor {
 	body := shape.Body()
+	if d.Heatmap != nil && body.GetType() == cp.BODY_DYNAMIC {
+		return toFColor(d.Heatmap.Color(d.Heatmap.Value(body)))
+	}
+	if c, ok := d.islandColor(shape); ok {
+		return c
+	}
 	if body.IsSleeping() {
 		return toFColor(d.Theme.ShapeSleeping)
 	}
@@ -238,40 +344,149 @@ func (d *Drawer) Data() interface{} {
 	return nil
 }
 
+// worldPath is a vector.Path built from world coordinates scaled to pixels.
+// ebiten flattens curves to within half a unit of the path as they are added,
+// so a path in world units would draw a small circle as a polygon once
+// PixelsPerUnit or GeoM scale it up.
+type worldPath struct {
+	vector.Path
+	// scale is how many path units one world unit spans.
+	scale float64
+}
+
+// newPath returns an empty path scaled to the pixels of the current camera.
+func (d *Drawer) newPath() *worldPath {
+	scale := d.screenScale()
+	if scale <= 0 || math.IsNaN(scale) || math.IsInf(scale, 0) {
+		scale = 1
+	}
+	return &worldPath{scale: scale}
+}
+
+func (p *worldPath) point(v cp.Vector) (float32, float32) {
+	return float32(v.X * p.scale), float32(v.Y * p.scale)
+}
+
+func (p *worldPath) MoveTo(v cp.Vector) {
+	p.Path.MoveTo(p.point(v))
+}
+
+func (p *worldPath) LineTo(v cp.Vector) {
+	p.Path.LineTo(p.point(v))
+}
+
+func (p *worldPath) Arc(center cp.Vector, radius, start, end float64, dir vector.Direction) {
+	x, y := p.point(center)
+	p.Path.Arc(x, y, float32(radius*p.scale), float32(start), float32(end), dir)
+}
+
+func (p *worldPath) ArcTo(a, b cp.Vector, radius float64) {
+	x1, y1 := p.point(a)
+	x2, y2 := p.point(b)
+	p.Path.ArcTo(x1, y1, x2, y2, float32(radius*p.scale))
+}
+
+// matrix returns the transform from the path to screen coordinates.
+func (p *worldPath) matrix(screenGeoM ebiten.GeoM) ebiten.GeoM {
+	var m ebiten.GeoM
+	m.Scale(1/p.scale, 1/p.scale)
+	m.Concat(screenGeoM)
+	return m
+}
+
 func (d *Drawer) drawOutline(
 	screen *ebiten.Image,
-	path vector.Path,
+	path *worldPath,
 	r, g, b, a float32,
 ) {
 	sop := &vector.StrokeOptions{}
-	sop.Width = d.StrokeWidth
+	sop.Width = d.StrokeWidth / float32(d.ppu()) * float32(path.scale)
 	sop.LineJoin = vector.LineJoinRound
 	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, sop)
-	applyMatrixToVertices(vs, *d.GeoM, &d.Camera, d.FlipYAxis, d.ScreenWidth, d.ScreenHeight, r, g, b, a)
+	applyMatrixToVertices(vs, path.matrix(d.ScreenGeoM()), r, g, b, a)
 	op := d.OptStroke
 	screen.DrawTriangles(vs, is, d.whiteImage, op)
+	d.countDraw(is)
 }
 
 func (d *Drawer) drawFill(
 	screen *ebiten.Image,
-	path vector.Path,
+	path *worldPath,
+	r, g, b, a float32,
+) {
+	if d.texturing != nil {
+		d.drawTexturedFill(screen, path, r, g, b, a)
+		return
+	}
+	d.drawSolidFill(screen, path, r, g, b, a)
+}
+
+func (d *Drawer) drawSolidFill(
+	screen *ebiten.Image,
+	path *worldPath,
 	r, g, b, a float32,
 ) {
 	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
-	applyMatrixToVertices(vs, *d.GeoM, &d.Camera, d.FlipYAxis, d.ScreenWidth, d.ScreenHeight, r, g, b, a)
+	applyMatrixToVertices(vs, path.matrix(d.ScreenGeoM()), r, g, b, a)
 	op := d.OptFill
 	screen.DrawTriangles(vs, is, d.whiteImage, op)
+	d.countDraw(is)
+}
+
+func (d *Drawer) countDraw(is []uint16) {
+	d.drawStats.Triangles += len(is) / 3
+	d.drawStats.DrawCalls++
+}
+
+// DrawStats returns what has been drawn since the last ResetDrawStats.
+func (d *Drawer) DrawStats() DrawStats {
+	return d.drawStats
+}
+
+func (d *Drawer) ResetDrawStats() {
+	d.drawStats = DrawStats{}
+}
+
+// ppu returns PixelsPerUnit, treating 0 as 1.
+func (d *Drawer) ppu() float64 {
+	if d.PixelsPerUnit <= 0 {
+		return 1
+	}
+	return d.PixelsPerUnit
 }
 
-func applyMatrixToVertices(vs []ebiten.Vertex, matrix ebiten.GeoM, camera *Camera, flipYAxis bool, screenWidth, screenHeight int, r, g, b, a float32) {
+// pixels converts a length in pixels to world units with an identity GeoM.
+// GeoM is applied in world units, so its zoom still scales the result.
+func (d *Drawer) pixels(n float64) float64 {
+	return n / d.ppu()
+}
+
+// ScreenGeoM returns the transform from world to screen coordinates that DrawSpace draws with.
+// Use it to draw images over bodies.
+func (d *Drawer) ScreenGeoM() ebiten.GeoM {
+	v := d.viewport()
+	center := cp.Vector{X: float64(v.Min.X) + float64(v.Dx())/2.0, Y: float64(v.Min.Y) + float64(v.Dy())/2.0}
+	return screenMatrix(*d.GeoM, d.Camera, d.FlipYAxis, d.ppu(), center)
+}
+
+// screenMatrix applies cameraGeoM in world units, then scales them to pixels,
+// flips the y axis unless flipYAxis and moves the camera to center.
+func screenMatrix(cameraGeoM ebiten.GeoM, camera Camera, flipYAxis bool, pixelsPerUnit float64, center cp.Vector) ebiten.GeoM {
 	var f float64 = -1
 	if flipYAxis {
 		f = 1
 	}
+	cameraGeoM.Scale(pixelsPerUnit, pixelsPerUnit*f)
+	cameraGeoM.Translate(-camera.Offset.X, -camera.Offset.Y*f)
+	cameraGeoM.Translate(center.X, center.Y)
+	return cameraGeoM
+}
+
+func screenCenter(screenWidth, screenHeight int) cp.Vector {
+	return cp.Vector{X: float64(screenWidth) / 2.0, Y: float64(screenHeight) / 2.0}
+}
 
-	matrix.Scale(1, f)
-	matrix.Translate(-camera.Offset.X, -camera.Offset.Y*f)
-	matrix.Translate(float64(screenWidth)/2.0, float64(screenHeight)/2.0)
+func applyMatrixToVertices(vs []ebiten.Vertex, matrix ebiten.GeoM, r, g, b, a float32) {
 	for i := range vs {
 		x, y := matrix.Apply(float64(vs[i].DstX), float64(vs[i].DstY))
 		vs[i].DstX, vs[i].DstY = float32(x), float32(y)
@@ -280,40 +495,77 @@ func applyMatrixToVertices(vs []ebiten.Vertex, matrix ebiten.GeoM, camera *Camer
 	}
 }
 
-// ScreenToWorld converts screen-space coordinates to world-space
+// WorldToScreen converts world-space coordinates to screen-space, the inverse of ScreenToWorld.
+// One world unit is one pixel; use Drawer.WorldToScreen to respect PixelsPerUnit.
+func WorldToScreen(worldPoint cp.Vector, cameraGeoM ebiten.GeoM, camera Camera, flipYAxis bool, screenWidth, screenHeight int) cp.Vector {
+	matrix := screenMatrix(cameraGeoM, camera, flipYAxis, 1, screenCenter(screenWidth, screenHeight))
+	x, y := matrix.Apply(worldPoint.X, worldPoint.Y)
+	return cp.Vector{X: x, Y: y}
+}
+
+// ScreenToWorld converts screen-space coordinates to world-space.
+// One world unit is one pixel; use Drawer.ScreenToWorld to respect PixelsPerUnit.
 func ScreenToWorld(screenPoint cp.Vector, cameraGeoM ebiten.GeoM, camera Camera, flipYAxis bool, screenWidth, screenHeight int) cp.Vector {
-	if cameraGeoM.IsInvertible() {
-		cameraGeoM.Invert()
-		var f float64 = -1
-		if flipYAxis {
-			f = 1
-		}
-		matrix := &ebiten.GeoM{}
-		matrix.Translate(-float64(screenWidth)/2.0, -float64(screenHeight)/2.0)
-		matrix.Translate(camera.Offset.X, camera.Offset.Y*f)
-		matrix.Scale(1, f)
-		matrix.Concat(cameraGeoM)
-		worldX, worldY := matrix.Apply(screenPoint.X, screenPoint.Y)
-		return cp.Vector{X: worldX, Y: worldY}
-	} else {
+	return invertScreenMatrix(screenMatrix(cameraGeoM, camera, flipYAxis, 1, screenCenter(screenWidth, screenHeight)), screenPoint)
+}
+
+func invertScreenMatrix(matrix ebiten.GeoM, screenPoint cp.Vector) cp.Vector {
+	if !matrix.IsInvertible() {
 		// When scaling it can happened that matrix is not invertable
 		return cp.Vector{X: math.NaN(), Y: math.NaN()}
 	}
+	matrix.Invert()
+	worldX, worldY := matrix.Apply(screenPoint.X, screenPoint.Y)
+	return cp.Vector{X: worldX, Y: worldY}
+}
+
+// WorldToScreen converts world-space coordinates to the screen the drawer draws on.
+func (d *Drawer) WorldToScreen(p cp.Vector) cp.Vector {
+	matrix := d.ScreenGeoM()
+	x, y := matrix.Apply(p.X, p.Y)
+	return cp.Vector{X: x, Y: y}
+}
+
+// ScreenToWorld converts coordinates on the screen the drawer draws on to world-space.
+func (d *Drawer) ScreenToWorld(p cp.Vector) cp.Vector {
+	return invertScreenMatrix(d.ScreenGeoM(), p)
 }
 
+// HandleMouseEvent lets the mouse or a touch drag bodies in space.
 func (d *Drawer) HandleMouseEvent(space *cp.Space) {
-	d.handler.handleMouseEvent(
-		d,
-		space,
-		d.ScreenWidth,
-		d.ScreenHeight,
-	)
+	d.HandleInput(space, d.ReadInput())
+}
+
+// ReadInput reads the pointer input of this frame.
+// Call it at most once per frame.
+func (d *Drawer) ReadInput() Input {
+	return d.handler.readInput(d)
+}
+
+// HandleInput drags bodies in space according to in.
+func (d *Drawer) HandleInput(space *cp.Space, in Input) {
+	d.handler.handleInput(space, in, d.pixels(grabRadius))
 }
 
 // event handling
 
+// Input is the pointer input consumed by HandleMouseEvent in one frame.
+type Input struct {
+	// Cursor is the pointer position in world coordinates.
+	Cursor cp.Vector `json:"cursor"`
+	// Pressed is true when the mouse button or a touch went down this frame.
+	Pressed bool `json:"pressed,omitempty"`
+	// Released is true when the mouse button or the touch went up this frame.
+	Released bool `json:"released,omitempty"`
+	// Touched is true when a touch went down this frame.
+	Touched bool `json:"touched,omitempty"`
+}
+
 const GRABBABLE_MASK_BIT uint = 1 << 31
 
+// grabRadius gives the mouse click a little radius in pixels to make it easier to click small shapes.
+const grabRadius = 5.0
+
 var grabFilter cp.ShapeFilter = cp.ShapeFilter{
 	Group:      cp.NO_GROUP,
 	Categories: GRABBABLE_MASK_BIT,
@@ -326,26 +578,22 @@ type mouseEventHandler struct {
 	touchIDs   []ebiten.TouchID
 }
 
-func (h *mouseEventHandler) handleMouseEvent(d *Drawer, space *cp.Space, screenWidth, screenHeight int) {
-	if h.mouseBody == nil {
-		h.mouseBody = cp.NewKinematicBody()
-	}
-
+func (h *mouseEventHandler) readInput(d *Drawer) Input {
+	var in Input
 	var x, y int
 
 	// touch position
 	for _, id := range h.touchIDs {
 		x, y = ebiten.TouchPosition(id)
 		if x == 0 && y == 0 || inpututil.IsTouchJustReleased(id) {
-			h.onMouseUp(space)
+			in.Released = true
 			h.touchIDs = []ebiten.TouchID{}
 			break
 		}
 	}
-	isJuestTouched := false
 	touchIDs := inpututil.AppendJustPressedTouchIDs(h.touchIDs[:0])
 	for _, id := range touchIDs {
-		isJuestTouched = true
+		in.Touched = true
 		h.touchIDs = []ebiten.TouchID{id}
 		x, y = ebiten.TouchPosition(id)
 		break
@@ -356,29 +604,93 @@ func (h *mouseEventHandler) handleMouseEvent(d *Drawer, space *cp.Space, screenW
 	}
 
 	cursorPosition := cp.Vector{X: float64(x), Y: float64(y)}
-	cursorPosition = ScreenToWorld(cursorPosition, *d.GeoM, d.Camera, d.FlipYAxis, screenWidth, screenHeight)
+	in.Cursor = d.ScreenToWorld(cursorPosition)
 
-	if isJuestTouched {
+	// Presses outside the viewport are left to the drawers of other viewports.
+	v := d.viewport()
+	inside := v.Empty() || image.Pt(x, y).In(v)
+	if (inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || in.Touched) && inside {
+		in.Pressed = true
+	}
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		in.Released = true
+	}
+	return in
+}
+
+// radius is the grab radius in world units.
+func (h *mouseEventHandler) handleInput(space *cp.Space, in Input, radius float64) {
+	if h.mouseBody == nil {
+		h.mouseBody = cp.NewKinematicBody()
+	}
+
+	if in.Touched {
 		h.mouseBody.SetVelocityVector(cp.Vector{})
-		h.mouseBody.SetPosition(cursorPosition)
+		h.mouseBody.SetPosition(in.Cursor)
 	} else {
-		newPoint := h.mouseBody.Position().Lerp(cursorPosition, 0.25)
+		newPoint := h.mouseBody.Position().Lerp(in.Cursor, 0.25)
 		h.mouseBody.SetVelocityVector(newPoint.Sub(h.mouseBody.Position()).Mult(60.0))
 		h.mouseBody.SetPosition(newPoint)
 	}
 
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || isJuestTouched {
-		h.onMouseDown(space, cursorPosition)
+	if in.Pressed {
+		h.onMouseDown(space, in.Cursor, radius)
 	}
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+	if in.Released {
 		h.onMouseUp(space)
 	}
 }
 
-func (h *mouseEventHandler) onMouseDown(space *cp.Space, cursorPosition cp.Vector) {
-	// give the mouse click a little radius to make it easier to click small shapes.
-	radius := 5.0
+// dragWhilePaused moves the grabbed body to the pointer,
+// because the mouse joint does nothing while the space is not stepped.
+func (h *mouseEventHandler) dragWhilePaused(space *cp.Space) {
+	if h.mouseJoint == nil {
+		return
+	}
+	_, body := constraintBodies(h.mouseJoint)
+	grabbed := body.LocalToWorld(h.mouseJoint.Class.(*cp.PivotJoint).AnchorB)
+	body.SetPosition(body.Position().Add(h.mouseBody.Position().Sub(grabbed)))
+	body.SetVelocityVector(cp.Vector{})
+	body.SetAngularVelocity(0)
+	reindexShapesForBody(space, body)
+}
+
+// reindexShapesForBody updates the bounding boxes of the shapes of body in the spatial
+// index of space after the body is moved without a step, so that queries find them where
+// they are drawn. The shapes stay in the space, so their arbiters and hash IDs are kept.
+func reindexShapesForBody(space *cp.Space, body *cp.Body) {
+	body.EachShape(func(shape *cp.Shape) {
+		shape.CacheBB()
+		// Shapes of sleeping bodies are kept in the static index.
+		for _, static := range []bool{false, true} {
+			index := spatialIndexer(spaceShapeIndex(space, static))
+			if !index.Contains(shape, shape.HashId()) {
+				continue
+			}
+			if tree, ok := index.(*cp.BBTree); ok {
+				// cp's BBTree does not implement ReindexObject.
+				tree.LeafUpdate(treeLeaf(tree, shape))
+			} else {
+				index.ReindexObject(shape, shape.HashId())
+			}
+			return
+		}
+	})
+}
+
+// reset drops the grabbed body from whichever space holds the joint and forgets the pointer position.
+func (h *mouseEventHandler) reset() {
+	if h.mouseJoint != nil {
+		// The joint may belong to a space that has been replaced since.
+		if space := constraintSpace(h.mouseJoint); space != nil {
+			space.RemoveConstraint(h.mouseJoint)
+		}
+	}
+	h.mouseJoint = nil
+	h.mouseBody = nil
+}
 
+func (h *mouseEventHandler) onMouseDown(space *cp.Space, cursorPosition cp.Vector, radius float64) {
 	info := space.PointQueryNearest(cursorPosition, radius, grabFilter)
 
 	if info.Shape != nil && info.Shape.Body().Mass() < cp.INFINITY {
@@ -0,0 +1,45 @@
+package ebitencp
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+func TestReindexShapesForBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		index func(space *cp.Space)
+	}{
+		{name: "bb tree", index: func(space *cp.Space) {}},
+		{name: "spatial hash", index: func(space *cp.Space) { space.UseSpatialHash(4, 100) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			space := cp.NewSpace()
+			tt.index(space)
+			body := addTestBox(space, cp.Vector{})
+			body.SetMass(42)
+			var shape *cp.Shape
+			body.EachShape(func(s *cp.Shape) { shape = s })
+			hashID := shape.HashId()
+			space.Step(1 / 60.0)
+
+			body.SetPosition(cp.Vector{X: 100, Y: 0})
+			reindexShapesForBody(space, body)
+
+			if info := space.PointQueryNearest(cp.Vector{X: 100, Y: 0}, 0, cp.SHAPE_FILTER_ALL); info.Shape != shape {
+				t.Error("the shape is not found where the body was moved")
+			}
+			if info := space.PointQueryNearest(cp.Vector{}, 0, cp.SHAPE_FILTER_ALL); info.Shape != nil {
+				t.Error("the shape is still found where the body was")
+			}
+			if shape.HashId() != hashID {
+				t.Errorf("hash ID changed from %d to %d", hashID, shape.HashId())
+			}
+			if body.Mass() != 42 {
+				t.Errorf("mass = %g, want 42", body.Mass())
+			}
+		})
+	}
+}
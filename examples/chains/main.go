@@ -3,56 +3,19 @@ package main
 // This is based on "jakecoffman/cp-examples/chains".
 
 import (
-	"fmt"
 	_ "image/png"
 	"log"
 
 	"github.com/demouth/ebitencp"
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 
 	"github.com/jakecoffman/cp/v2"
 )
 
 const (
-	screenWidth  = 640
-	screenHeight = 480
-	hwidth       = screenWidth / 2
-	hheight      = screenHeight / 2
-
 	CHAIN_COUNT = 8
 	LINK_COUNT  = 10
 )
 
-type Game struct {
-	count  int
-	space  *cp.Space
-	drawer *ebitencp.Drawer
-}
-
-func (g *Game) Update() error {
-	g.drawer.HandleMouseEvent(g.space)
-
-	g.space.Step(1 / 60.0)
-	return nil
-}
-
-func (g *Game) Draw(screen *ebiten.Image) {
-
-	g.drawer.Screen = screen
-	cp.DrawSpace(g.space, g.drawer)
-
-	msg := fmt.Sprintf(
-		"FPS: %0.2f",
-		ebiten.ActualFPS(),
-	)
-	ebitenutil.DebugPrint(screen, msg)
-}
-
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return screenWidth, screenHeight
-}
-
 func main() {
 
 	// Initialising Chipmunk
@@ -103,7 +66,7 @@ func main() {
 			}
 
 			constraint.SetMaxForce(breakingForce)
-			constraint.PostSolve = BreakableJointPostSolve
+			ebitencp.MakeBreakable(constraint, ebitencp.DefaultBreakThreshold, nil)
 			constraint.SetCollideBodies(false)
 
 			prev = body
@@ -121,30 +84,29 @@ func main() {
 
 	// Initialising Ebitengine/v2
 
-	game := &Game{}
-	game.space = space
-	game.drawer = ebitencp.NewDrawer(screenWidth, screenHeight)
-
-	ebiten.SetWindowSize(screenWidth, screenHeight)
-	ebiten.SetWindowTitle("ebiten-chipmunk - chains")
-	if err := ebiten.RunGame(game); err != nil {
+	runner := ebitencp.NewRunner(space, &ebitencp.RunnerOptions{
+		Title:    "ebiten-chipmunk - chains",
+		DebugHUD: true,
+	})
+	control, err := ebitencp.NewSimulationControl(runner.Drawer, space)
+	if err != nil {
 		log.Fatal(err)
 	}
-}
-
-func BreakableJointPostStepRemove(space *cp.Space, joint interface{}, _ interface{}) {
-	space.RemoveConstraint(joint.(*cp.Constraint))
-}
-
-func BreakableJointPostSolve(joint *cp.Constraint, space *cp.Space) {
-	dt := space.TimeStep()
-
-	// Convert the impulse to a force by dividing it by the timestep.
-	force := joint.Class.GetImpulse() / dt
-	maxForce := joint.MaxForce()
-
-	// If the force is almost as big as the joint's max force, break it.
-	if force > 0.9*maxForce {
-		space.AddPostStepCallback(BreakableJointPostStepRemove, joint, nil)
+	// Keep the last 10 seconds to find the step a chain breaks at.
+	// Seeking back before it adds the broken link again.
+	control.History, err = ebitencp.NewHistory(runner.Drawer, space, 600)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Reset restores the chains from a snapshot, which has no callbacks.
+	control.Restored = func(space *cp.Space) {
+		space.EachConstraint(func(c *cp.Constraint) {
+			ebitencp.MakeBreakable(c, ebitencp.DefaultBreakThreshold, nil)
+		})
+	}
+	runner.Control = control
+	runner.Drawer.StressColors = true
+	if err := runner.Run(); err != nil {
+		log.Fatal(err)
 	}
 }
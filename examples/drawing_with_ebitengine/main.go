@@ -20,8 +20,9 @@ const (
 )
 
 var (
-	space  *cp.Space
-	drawer *ebitencp.Drawer
+	space     *cp.Space
+	drawer    *ebitencp.Drawer
+	animators []*ebitencp.SpriteAnimator
 
 	drawingWithEbitengine = true
 )
@@ -38,6 +39,9 @@ type Camera struct {
 func (g *Game) Update() error {
 	space.Step(1 / 60.0)
 	drawer.HandleMouseEvent(space)
+	for _, a := range animators {
+		a.Update(1 / 60.0)
+	}
 	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
 		drawingWithEbitengine = !drawingWithEbitengine
 	}
@@ -80,43 +84,19 @@ func (g *Game) Update() error {
 	drawer.GeoM.Translate(g.camera.Offset.X, g.camera.Offset.Y)
 	drawer.GeoM.Scale(g.camera.Zoom, g.camera.Zoom)
 	drawer.GeoM.Rotate(g.camera.Rotate)
-	drawer.GeoM.Translate(screenWidth/2, screenHeight/2)
 	return nil
 }
 func (g *Game) Draw(screen *ebiten.Image) {
+	// The drawer centers the camera on the screen it draws into.
+	drawer.WithScreen(screen)
 	if drawingWithEbitengine {
 		space.EachShape(func(s *cp.Shape) {
-			switch s.Class.(type) {
-			case *cp.Circle:
-				circle := s.Class.(*cp.Circle)
-				body := circle.Body()
-				util.DrawRunner(
-					screen,
-					*drawer.GeoM,
-					float32(body.Position().X),
-					float32(body.Position().Y),
-					float32(circle.Radius()),
-					float32(circle.Body().Angle()),
-				)
-			case *cp.PolyShape:
-				poly := s.Class.(*cp.PolyShape)
-				body := poly.Body()
-				r := (poly.TransformVert(0).Distance(poly.TransformVert(1))) * 0.5
-				util.DrawRunner(
-					screen,
-					*drawer.GeoM,
-					float32(body.Position().X),
-					float32(body.Position().Y),
-					float32(r),
-					float32(poly.Body().Angle()),
-				)
-			case *cp.Segment:
-				segment := s.Class.(*cp.Segment)
+			if segment, ok := s.Class.(*cp.Segment); ok {
 				ta := segment.TransformA()
 				tb := segment.TransformB()
 				util.DrawLine(
 					screen,
-					*drawer.GeoM,
+					drawer.ScreenGeoM(),
 					float32(ta.X), float32(ta.Y),
 					float32(tb.X), float32(tb.Y),
 					float32(segment.Radius()*2),
@@ -124,8 +104,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 				)
 			}
 		})
+		drawer.DrawSprites()
 	} else {
-		cp.DrawSpace(space, drawer.WithScreen(screen))
+		// The outlines of the shapes are drawn over the sprites.
+		drawer.DrawSpace(space)
 	}
 	ebitenutil.DebugPrint(
 		screen,
@@ -167,6 +149,16 @@ func main() {
 	game := &Game{}
 	drawer = ebitencp.NewDrawer(0, 0)
 	drawer.FlipYAxis = true
+	drawer.Sprites = ebitencp.NewSpriteLayer()
+	drawer.Sprites.Shapes = true
+	space.EachShape(func(s *cp.Shape) {
+		switch class := s.Class.(type) {
+		case *cp.Circle:
+			addRunner(s, class.Radius())
+		case *cp.PolyShape:
+			addRunner(s, class.Vert(0).Distance(class.Vert(1))*0.5)
+		}
+	})
 	game.camera = Camera{
 		Zoom: 1,
 		// Set the camera offset to the center of the screen
@@ -175,6 +167,31 @@ func main() {
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.RunGame(game)
 }
+
+// addRunner draws a runner over shape, fitted to radius,
+// which idles, runs and jumps with the body of shape.
+func addRunner(shape *cp.Shape, radius float64) {
+	const frameSize = 32
+	sheet := util.RunnerImage
+	idle := ebitencp.SheetFrames(sheet, 0, 0, frameSize, frameSize, 5)
+	sprite := ebitencp.NewSprite(idle[0])
+	// Draw the runner a little higher than the center of the shape.
+	sprite.Anchor.Y = (frameSize/2 + 3) / float64(frameSize)
+	scale := radius / frameSize * 2 * 1.3
+	sprite.Scale = cp.Vector{X: scale, Y: scale}
+	drawer.Sprites.AttachShape(shape, sprite)
+
+	animator := ebitencp.NewSpriteAnimator(sprite, map[ebitencp.AnimationState]*ebitencp.Animation{
+		ebitencp.AnimationIdle:     {Frames: idle, FrameTime: 0.15, Loop: true},
+		ebitencp.AnimationMoving:   {Frames: ebitencp.SheetFrames(sheet, 0, frameSize, frameSize, frameSize, 8), FrameTime: 0.08, Loop: true},
+		ebitencp.AnimationAirborne: {Frames: ebitencp.SheetFrames(sheet, 0, frameSize*2, frameSize, frameSize, 4), FrameTime: 0.1},
+		ebitencp.AnimationSleeping: {Frames: idle[:1]},
+	})
+	// Gravity points to +Y in this example.
+	animator.Up = cp.Vector{X: 0, Y: -1}
+	animators = append(animators, animator)
+}
+
 func addBall(space *cp.Space, x, y, radius float64) *cp.Body {
 	mass := radius * radius / 100.0
 	body := space.AddBody(
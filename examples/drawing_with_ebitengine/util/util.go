@@ -13,8 +13,9 @@ import (
 )
 
 var (
-	whiteImage  *ebiten.Image
-	runnerImage *ebiten.Image
+	whiteImage *ebiten.Image
+	// RunnerImage is a sprite sheet of 32x32 frames.
+	RunnerImage *ebiten.Image
 )
 
 func init() {
@@ -26,7 +27,7 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	runnerImage = ebiten.NewImageFromImage(img)
+	RunnerImage = ebiten.NewImageFromImage(img)
 }
 
 func DrawLine(screen *ebiten.Image, mat ebiten.GeoM, x1, y1, x2, y2, width float32, c color.RGBA) {
@@ -72,16 +73,3 @@ func DrawCircle(screen *ebiten.Image, mat ebiten.GeoM, x, y, radius float32, c c
 	path.Arc(x, y, radius, 0, 2*math.Pi, vector.Clockwise)
 	DrawFill(screen, mat, path, c)
 }
-func DrawRunner(screen *ebiten.Image, mat ebiten.GeoM, x, y, radius, rotate float32) {
-	const frameSize = 32
-	r := radius / frameSize * 2
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(-frameSize/2, -frameSize/2)
-	op.GeoM.Translate(0, -3) // fine tuning
-	op.GeoM.Scale(float64(r), float64(r))
-	op.GeoM.Scale(1.3, 1.3) // fine tuning
-	op.GeoM.Rotate(float64(rotate))
-	op.GeoM.Translate(float64(x), float64(y))
-	op.GeoM.Concat(mat)
-	screen.DrawImage(runnerImage.SubImage(image.Rect(0, 0, frameSize, frameSize)).(*ebiten.Image), op)
-}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log"
+
+	"github.com/demouth/ebitencp"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// The space is built in meters and drawn at 50 pixels per meter,
+// so the 640x480 screen spans 12.8x9.6 meters.
+func main() {
+	space := cp.NewSpace()
+	space.SetGravity(cp.Vector{X: 0, Y: -9.8})
+	walls := []cp.Vector{{X: -6, Y: -4.5}, {X: 6, Y: -4.5}, {X: -6, Y: -4.5}, {X: -6, Y: 4.5}, {X: 6, Y: -4.5}, {X: 6, Y: 4.5}}
+	for i := 0; i < len(walls)-1; i += 2 {
+		space.AddShape(cp.NewSegment(space.StaticBody, walls[i], walls[i+1], 0)).SetFriction(0.5)
+	}
+	ramp := space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: -5, Y: 1}, cp.Vector{X: 1, Y: -2}, 0.05))
+	ramp.SetFriction(0.5)
+
+	// A ball 1 meter across, drawn 50 pixels across with 1 pixel outlines
+	body := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, 0.5, cp.Vector{})))
+	body.SetPosition(cp.Vector{X: -4, Y: 3})
+	space.AddShape(cp.NewCircle(body, 0.5, cp.Vector{})).SetFriction(0.5)
+
+	box := space.AddBody(cp.NewBody(2, cp.MomentForBox(2, 1, 0.5)))
+	box.SetPosition(cp.Vector{X: 3, Y: 0})
+	space.AddShape(cp.NewBox(box, 1, 0.5, 0)).SetFriction(0.5)
+
+	runner := ebitencp.NewRunner(space, &ebitencp.RunnerOptions{Title: "ebiten-chipmunk - meters", DebugHUD: true})
+	runner.Drawer.PixelsPerUnit = 50
+	runner.Drawer.Grid = ebitencp.NewGridOverlay()
+	motion := ebitencp.NewMotionOverlay()
+	motion.Show = ebitencp.MotionVelocity | ebitencp.MotionCenterOfGravity
+	runner.Drawer.Motion = motion
+	if err := runner.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"image"
+	"log"
+	"math/rand/v2"
+
+	"github.com/demouth/ebitencp"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/jakecoffman/cp/v2"
+)
+
+const (
+	screenWidth  = 640
+	screenHeight = 480
+	levelWidth   = 4000
+	levelHeight  = 1200
+)
+
+// A level much larger than the screen with a minimap in the top right corner.
+type Game struct {
+	space   *cp.Space
+	drawer  *ebitencp.Drawer
+	minimap *ebitencp.Minimap
+	camera  cp.Vector
+}
+
+func (g *Game) Update() error {
+	if p, ok := g.minimap.HandleMouseEvent(); ok {
+		g.camera = p
+	} else {
+		g.drawer.HandleMouseEvent(g.space)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyA) {
+		g.camera.X -= 8
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyD) {
+		g.camera.X += 8
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyW) {
+		g.camera.Y += 8
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyS) {
+		g.camera.Y -= 8
+	}
+	g.drawer.GeoM.Reset()
+	g.drawer.GeoM.Translate(-g.camera.X, -g.camera.Y)
+	g.space.Step(1 / 60.0)
+	return nil
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+	g.drawer.WithScreen(screen).DrawSpace(g.space)
+	g.minimap.Draw(screen, g.space)
+	ebitenutil.DebugPrint(screen, "Camera = WASD\nJump = Click the minimap\nDrag Object = Cursor")
+}
+
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
+
+func main() {
+	space := cp.NewSpace()
+	space.SetGravity(cp.Vector{X: 0, Y: -300})
+	walls := []cp.Vector{
+		{X: 0, Y: 0}, {X: levelWidth, Y: 0},
+		{X: 0, Y: 0}, {X: 0, Y: levelHeight},
+		{X: levelWidth, Y: 0}, {X: levelWidth, Y: levelHeight},
+	}
+	for i := 0; i < len(walls)-1; i += 2 {
+		space.AddShape(cp.NewSegment(space.StaticBody, walls[i], walls[i+1], 4)).SetFriction(0.7)
+	}
+	// Hills along the floor
+	for x := 200.0; x < levelWidth; x += 400 {
+		h := 50 + rand.Float64()*150
+		space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: x - 150, Y: 0}, cp.Vector{X: x, Y: h}, 4)).SetFriction(0.7)
+		space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: x, Y: h}, cp.Vector{X: x + 150, Y: 0}, 4)).SetFriction(0.7)
+	}
+	for i := 0; i < 200; i++ {
+		size := 10 + rand.Float64()*20
+		body := space.AddBody(cp.NewBody(1, cp.MomentForBox(1, size, size)))
+		body.SetPosition(cp.Vector{X: 50 + rand.Float64()*(levelWidth-100), Y: 300 + rand.Float64()*(levelHeight-400)})
+		space.AddShape(cp.NewBox(body, size, size, 0)).SetFriction(0.7)
+	}
+
+	drawer := ebitencp.NewDrawer(0, 0)
+	g := &Game{
+		space:   space,
+		drawer:  drawer,
+		minimap: ebitencp.NewMinimap(drawer, image.Rect(screenWidth-248, 8, screenWidth-8, 8+72)),
+		camera:  cp.Vector{X: screenWidth / 2, Y: screenHeight / 2},
+	}
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle("ebiten-chipmunk - minimap")
+	if err := ebiten.RunGame(g); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"log"
+
+	"github.com/demouth/ebitencp"
+	"github.com/jakecoffman/cp/v2"
+)
+
+func main() {
+	space := cp.NewSpace()
+	space.SetGravity(cp.Vector{X: 0, Y: -100})
+	walls := []cp.Vector{{X: -320, Y: -240}, {X: 320, Y: -240}, {X: -320, Y: -240}, {X: -320, Y: 240}, {X: 320, Y: -240}, {X: 320, Y: 240}}
+	for i := 0; i < len(walls)-1; i += 2 {
+		space.AddShape(cp.NewSegment(space.StaticBody, walls[i], walls[i+1], 0)).SetFriction(0.5)
+	}
+	body := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, 30, cp.Vector{})))
+	space.AddShape(cp.NewCircle(body, 30, cp.Vector{})).SetFriction(0.5)
+
+	runner := ebitencp.NewRunner(space, &ebitencp.RunnerOptions{Title: "ebiten-chipmunk - runner", DebugHUD: true})
+	control, err := ebitencp.NewSimulationControl(runner.Drawer, space)
+	if err != nil {
+		log.Fatal(err)
+	}
+	runner.Control = control
+	runner.Drawer.Motion = ebitencp.NewMotionOverlay()
+	if err := runner.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
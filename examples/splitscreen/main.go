@@ -0,0 +1,110 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"log"
+
+	"github.com/demouth/ebitencp"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/jakecoffman/cp/v2"
+)
+
+const (
+	screenWidth  = 960
+	screenHeight = 480
+)
+
+// Two drawers with their own cameras share one screen,
+// side by side or with the second one as a picture-in-picture.
+type Game struct {
+	space *cp.Space
+	ball1 *cp.Body
+	ball2 *cp.Body
+	left  *ebitencp.Drawer
+	right *ebitencp.Drawer
+	inset bool
+}
+
+func (g *Game) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.inset = !g.inset
+	}
+	g.layoutViewports()
+	// Each drawer grabs the bodies pressed in its own viewport.
+	// The inset is on top of the left viewport, so presses in it are only for the right drawer.
+	g.right.HandleMouseEvent(g.space)
+	in := g.left.ReadInput()
+	if g.inset && image.Pt(ebiten.CursorPosition()).In(g.right.Viewport) {
+		in.Pressed = false
+	}
+	g.left.HandleInput(g.space, in)
+
+	g.left.GeoM.Reset()
+	g.left.GeoM.Translate(-g.ball1.Position().X, -g.ball1.Position().Y)
+	g.right.GeoM.Reset()
+	g.right.GeoM.Translate(-g.ball2.Position().X, -g.ball2.Position().Y)
+	g.right.GeoM.Scale(2, 2)
+
+	g.space.Step(1 / 60.0)
+	return nil
+}
+
+func (g *Game) layoutViewports() {
+	if g.inset {
+		g.left.Viewport = image.Rect(0, 0, screenWidth, screenHeight)
+		g.right.Viewport = image.Rect(screenWidth-256-8, 8, screenWidth-8, 8+192)
+		return
+	}
+	g.left.Viewport = image.Rect(0, 0, screenWidth/2-1, screenHeight)
+	g.right.Viewport = image.Rect(screenWidth/2+1, 0, screenWidth, screenHeight)
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+	g.left.WithScreen(screen).DrawSpace(g.space)
+	// WithScreen draws into the sub-image of the viewport, which clips the drawing.
+	g.right.WithScreen(screen)
+	if g.inset {
+		g.right.Screen.Fill(color.RGBA{0x20, 0x20, 0x20, 0xff})
+	}
+	g.right.DrawSpace(g.space)
+	ebitenutil.DebugPrint(screen, "Space = Toggle split-screen and picture-in-picture\nDrag Object = Cursor")
+}
+
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
+
+func main() {
+	space := cp.NewSpace()
+	space.SetGravity(cp.Vector{X: 0, Y: -100})
+	walls := []cp.Vector{{X: -400, Y: -200}, {X: 400, Y: -200}, {X: -400, Y: -200}, {X: -400, Y: 200}, {X: 400, Y: -200}, {X: 400, Y: 200}}
+	for i := 0; i < len(walls)-1; i += 2 {
+		shape := space.AddShape(cp.NewSegment(space.StaticBody, walls[i], walls[i+1], 2))
+		shape.SetElasticity(0.5)
+		shape.SetFriction(0.5)
+	}
+	g := &Game{
+		space: space,
+		ball1: addBall(space, -200, 0, 30),
+		ball2: addBall(space, 200, 0, 15),
+		left:  ebitencp.NewDrawer(0, 0),
+		right: ebitencp.NewDrawer(0, 0),
+	}
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle("ebiten-chipmunk - split-screen")
+	if err := ebiten.RunGame(g); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func addBall(space *cp.Space, x, y, radius float64) *cp.Body {
+	body := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, radius, cp.Vector{})))
+	body.SetPosition(cp.Vector{X: x, Y: y})
+	shape := space.AddShape(cp.NewCircle(body, radius, cp.Vector{}))
+	shape.SetElasticity(0.5)
+	shape.SetFriction(0.5)
+	return body
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/demouth/ebitencp"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+const crateType cp.CollisionType = 1
+
+// Crates, plates and terrain filled with textures made at startup.
+func main() {
+	space := cp.NewSpace()
+	space.SetGravity(cp.Vector{X: 0, Y: -100})
+
+	ground := space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: -300, Y: -200}, cp.Vector{X: 300, Y: -200}, 20))
+	ground.SetFriction(0.7)
+	ramp := space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: -300, Y: 0}, cp.Vector{X: -60, Y: -100}, 10))
+	ramp.SetFriction(0.7)
+
+	for i := 0; i < 6; i++ {
+		body := space.AddBody(cp.NewBody(1, cp.MomentForBox(1, 50, 50)))
+		body.SetPosition(cp.Vector{X: float64(i)*60 - 30, Y: 100 + float64(i)*40})
+		crate := space.AddShape(cp.NewBox(body, 50, 50, 0))
+		crate.SetFriction(0.7)
+		crate.SetCollisionType(crateType)
+	}
+	plate := space.AddBody(cp.NewBody(2, cp.MomentForBox(2, 160, 20)))
+	plate.SetPosition(cp.Vector{X: 150, Y: 200})
+	plateShape := space.AddShape(cp.NewBox(plate, 160, 20, 4))
+	plateShape.SetFriction(0.7)
+	ball := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, 30, cp.Vector{})))
+	ball.SetPosition(cp.Vector{X: -200, Y: 100})
+	ballShape := space.AddShape(cp.NewCircle(ball, 30, cp.Vector{}))
+	ballShape.SetFriction(0.7)
+
+	runner := ebitencp.NewRunner(space, &ebitencp.RunnerOptions{Title: "ebiten-chipmunk - textures"})
+	textures := ebitencp.NewTextureFills()
+
+	// Each crate shows the whole image.
+	crate := ebitencp.NewTexture(crateImage())
+	crate.Mapping = ebitencp.TextureStretch
+	textures.SetCollisionType(crateType, crate)
+
+	// The plate and the ball tile a checker pattern, tinted by the shape color.
+	checker := ebitencp.NewTexture(checkerImage())
+	checker.Scale = 0.5
+	checker.Tint = true
+	textures.SetShape(plateShape, checker)
+	textures.SetShape(ballShape, checker)
+
+	terrain := ebitencp.NewTexture(terrainImage())
+	textures.SetShape(ground, terrain)
+	textures.SetShape(ramp, terrain)
+
+	runner.Drawer.Textures = textures
+	if err := runner.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func crateImage() *ebiten.Image {
+	img := ebiten.NewImage(32, 32)
+	img.Fill(color.RGBA{0x9c, 0x6b, 0x30, 0xff})
+	dark := color.RGBA{0x5e, 0x3c, 0x14, 0xff}
+	for _, y := range []float32{10, 21} {
+		vector.StrokeLine(img, 0, y, 32, y, 1, dark, false)
+	}
+	vector.StrokeRect(img, 1, 1, 30, 30, 2, dark, false)
+	vector.StrokeLine(img, 2, 2, 30, 30, 2, dark, true)
+	return img
+}
+
+func checkerImage() *ebiten.Image {
+	img := ebiten.NewImage(16, 16)
+	img.Fill(color.White)
+	gray := color.RGBA{0xa0, 0xa0, 0xa0, 0xff}
+	vector.DrawFilledRect(img, 0, 0, 8, 8, gray, false)
+	vector.DrawFilledRect(img, 8, 8, 8, 8, gray, false)
+	return img
+}
+
+func terrainImage() *ebiten.Image {
+	img := ebiten.NewImage(16, 16)
+	img.Fill(color.RGBA{0x6b, 0x4a, 0x2b, 0xff})
+	stone := color.RGBA{0x8a, 0x6e, 0x52, 0xff}
+	vector.DrawFilledCircle(img, 4, 5, 2, stone, true)
+	vector.DrawFilledCircle(img, 12, 11, 3, stone, true)
+	return img
+}
@@ -0,0 +1,149 @@
+package ebitencp
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// GridOverlay draws a world-aligned grid under the space. Set it to Drawer.Grid.
+//
+// The spacing is the smallest of 1, 2 or 5 times a power of ten that keeps
+// the lines MinSpacing pixels apart, so it adapts to the zoom of GeoM.
+type GridOverlay struct {
+	// MinSpacing is the least distance between grid lines in pixels.
+	MinSpacing float64
+	// Axes draws the X and Y axes through the origin.
+	Axes bool
+	// Labels draws the coordinates of the grid lines along the axes.
+	Labels bool
+	// ScaleBar draws a bar with its length in world units in the bottom left corner of the viewport.
+	ScaleBar bool
+	Face     text.Face
+}
+
+func NewGridOverlay() *GridOverlay {
+	return &GridOverlay{
+		MinSpacing: 40,
+		Axes:       true,
+		Labels:     true,
+		ScaleBar:   true,
+		Face:       DefaultFace(),
+	}
+}
+
+// niceStep returns the smallest of 1, 2 or 5 times a power of ten that is at least least.
+func niceStep(least float64) float64 {
+	p := math.Pow(10, math.Floor(math.Log10(least)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if p*m >= least {
+			return p * m
+		}
+	}
+	return p * 10
+}
+
+// formatStep formats v, a multiple of step, with as many decimals as step has,
+// so that values like 0.30000000000000004 print as 0.3.
+func formatStep(v, step float64) string {
+	decimals := max(0, int(-math.Floor(math.Log10(step))))
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+// screenScale returns how many pixels one world unit spans on screen,
+// with PixelsPerUnit and the zoom of GeoM.
+func (d *Drawer) screenScale() float64 {
+	return d.WorldToScreen(cp.Vector{X: 1}).Sub(d.WorldToScreen(cp.Vector{})).Length()
+}
+
+// visibleBB returns the world box that covers the viewport.
+func (d *Drawer) visibleBB() (cp.BB, bool) {
+	v := d.viewport()
+	l, t, r, b := float64(v.Min.X), float64(v.Min.Y), float64(v.Max.X), float64(v.Max.Y)
+	bb := cp.BB{L: math.Inf(1), B: math.Inf(1), R: math.Inf(-1), T: math.Inf(-1)}
+	for _, p := range []cp.Vector{{X: l, Y: t}, {X: r, Y: t}, {X: l, Y: b}, {X: r, Y: b}} {
+		v := d.ScreenToWorld(p)
+		if math.IsNaN(v.X) {
+			return bb, false
+		}
+		bb = bb.Expand(v)
+	}
+	return bb, true
+}
+
+func (d *Drawer) drawGrid() {
+	g := d.Grid
+	ppu := d.screenScale()
+	bb, ok := d.visibleBB()
+	if !ok || ppu == 0 {
+		return
+	}
+	step := niceStep(g.MinSpacing / ppu)
+	x0, x1 := math.Ceil(bb.L/step), math.Floor(bb.R/step)
+	y0, y1 := math.Ceil(bb.B/step), math.Floor(bb.T/step)
+	// Too many lines would only fill the screen.
+	if x1-x0 > 1000 || y1-y0 > 1000 {
+		return
+	}
+
+	path := d.newPath()
+	for i := x0; i <= x1; i++ {
+		path.MoveTo(cp.Vector{X: i * step, Y: bb.B})
+		path.LineTo(cp.Vector{X: i * step, Y: bb.T})
+	}
+	for i := y0; i <= y1; i++ {
+		path.MoveTo(cp.Vector{X: bb.L, Y: i * step})
+		path.LineTo(cp.Vector{X: bb.R, Y: i * step})
+	}
+	clr := toFColor(d.Theme.Grid)
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+
+	if g.Axes {
+		d.DrawSegment(cp.Vector{X: bb.L}, cp.Vector{X: bb.R}, toFColor(d.Theme.GridAxisX), nil)
+		d.DrawSegment(cp.Vector{Y: bb.B}, cp.Vector{Y: bb.T}, toFColor(d.Theme.GridAxisY), nil)
+	}
+	if g.Labels {
+		textColor := color.NRGBA(d.Theme.GridLabel)
+		for i := x0; i <= x1; i++ {
+			p := d.WorldToScreen(cp.Vector{X: i * step})
+			drawText(d.Screen, formatStep(i*step, step), g.Face, p.X+2, p.Y+2, textColor)
+		}
+		for i := y0; i <= y1; i++ {
+			if i == 0 {
+				continue
+			}
+			p := d.WorldToScreen(cp.Vector{Y: i * step})
+			drawText(d.Screen, formatStep(i*step, step), g.Face, p.X+2, p.Y+2, textColor)
+		}
+	}
+	if g.ScaleBar {
+		d.drawScaleBar(ppu)
+	}
+}
+
+func (d *Drawer) drawScaleBar(ppu float64) {
+	const margin, width = 8, 100
+	// The largest of 1, 2 or 5 times a power of ten that fits in width
+	limit := width / ppu
+	p := math.Pow(10, math.Floor(math.Log10(limit)))
+	units := p
+	for _, m := range []float64{5, 2} {
+		if p*m <= limit {
+			units = p * m
+			break
+		}
+	}
+	w := float32(units * ppu)
+	v := d.viewport()
+	x := float32(v.Min.X + margin)
+	y := float32(v.Max.Y - margin)
+	clr := color.NRGBA(d.Theme.GridLabel)
+	vector.StrokeLine(d.Screen, x, y, x+w, y, 2, clr, true)
+	vector.StrokeLine(d.Screen, x, y-4, x, y, 2, clr, true)
+	vector.StrokeLine(d.Screen, x+w, y-4, x+w, y, 2, clr, true)
+	drawText(d.Screen, formatStep(units, units), d.Grid.Face, float64(x), float64(y)-4-lineSpacing(d.Grid.Face), clr)
+}
@@ -0,0 +1,43 @@
+package ebitencp
+
+import "testing"
+
+func TestNiceStep(t *testing.T) {
+	tests := []struct {
+		least float64
+		want  float64
+	}{
+		{least: 1, want: 1},
+		{least: 1.5, want: 2},
+		{least: 3, want: 5},
+		{least: 7, want: 10},
+		{least: 40, want: 50},
+		{least: 0.04, want: 0.05},
+		{least: 0.15, want: 0.2},
+	}
+	for _, tt := range tests {
+		if got := niceStep(tt.least); got != tt.want {
+			t.Errorf("niceStep(%g) = %g, want %g", tt.least, got, tt.want)
+		}
+	}
+}
+
+func TestFormatStep(t *testing.T) {
+	tests := []struct {
+		v, step float64
+		want    string
+	}{
+		{v: 3 * 0.1, step: 0.1, want: "0.3"},
+		{v: 7 * 0.2, step: 0.2, want: "1.4"},
+		{v: 3 * 0.05, step: 0.05, want: "0.15"},
+		{v: -4 * 0.5, step: 0.5, want: "-2.0"},
+		{v: 150, step: 50, want: "150"},
+		{v: 2, step: 2, want: "2"},
+		{v: 20000, step: 10000, want: "20000"},
+	}
+	for _, tt := range tests {
+		if got := formatStep(tt.v, tt.step); got != tt.want {
+			t.Errorf("formatStep(%g, %g) = %q, want %q", tt.v, tt.step, got, tt.want)
+		}
+	}
+}
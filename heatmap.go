@@ -0,0 +1,121 @@
+package ebitencp
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// HeatQuantity is the body quantity a Heatmap shows.
+type HeatQuantity int
+
+const (
+	HeatSpeed HeatQuantity = iota
+	HeatKineticEnergy
+	HeatAngularVelocity
+)
+
+func (q HeatQuantity) String() string {
+	switch q {
+	case HeatSpeed:
+		return "Speed"
+	case HeatKineticEnergy:
+		return "Kinetic energy"
+	case HeatAngularVelocity:
+		return "Angular velocity"
+	}
+	return fmt.Sprintf("HeatQuantity(%d)", int(q))
+}
+
+// Heatmap colors the shapes of dynamic bodies by a quantity on a color ramp.
+// Set it to Drawer.Heatmap, where it takes the place of the Theme shape colors.
+type Heatmap struct {
+	Quantity HeatQuantity
+	// Min and Max are the values at the ends of Ramp. Values outside are clamped.
+	Min, Max float64
+	// Ramp is the colors from Min to Max, evenly spaced.
+	Ramp []color.RGBA
+}
+
+// NewHeatmap returns a heatmap of q with a blue to red ramp and a range
+// that suits bodies of about unit mass moving at a few hundred units per second.
+func NewHeatmap(q HeatQuantity) *Heatmap {
+	h := &Heatmap{
+		Quantity: q,
+		Ramp: []color.RGBA{
+			{0x33, 0x4C, 0xFF, 0x80},
+			{0x33, 0xCC, 0xFF, 0x80},
+			{0x33, 0xE5, 0x4C, 0x80},
+			{0xFF, 0xE5, 0x33, 0x80},
+			{0xFF, 0x33, 0x19, 0x80},
+		},
+	}
+	switch q {
+	case HeatSpeed:
+		h.Max = 300
+	case HeatKineticEnergy:
+		h.Max = 50000
+	case HeatAngularVelocity:
+		h.Max = 10
+	}
+	return h
+}
+
+// Value returns the quantity of body.
+func (h *Heatmap) Value(body *cp.Body) float64 {
+	switch h.Quantity {
+	case HeatKineticEnergy:
+		v, w := body.Velocity(), body.AngularVelocity()
+		return 0.5 * (body.Mass()*v.LengthSq() + body.Moment()*w*w)
+	case HeatAngularVelocity:
+		return math.Abs(body.AngularVelocity())
+	}
+	return body.Velocity().Length()
+}
+
+// Color returns the color of value on the ramp.
+func (h *Heatmap) Color(value float64) color.RGBA {
+	if len(h.Ramp) == 0 {
+		return color.RGBA{}
+	}
+	t := 0.0
+	if h.Max > h.Min {
+		t = cp.Clamp01((value - h.Min) / (h.Max - h.Min))
+	}
+	f := t * float64(len(h.Ramp)-1)
+	i := int(f)
+	if i >= len(h.Ramp)-1 {
+		return h.Ramp[len(h.Ramp)-1]
+	}
+	a, b := h.Ramp[i], h.Ramp[i+1]
+	f -= float64(i)
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*f + 0.5)
+	}
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), lerp(a.A, b.A)}
+}
+
+// DrawLegend draws the ramp and its range in the bottom right corner of screen.
+func (h *Heatmap) DrawLegend(screen *ebiten.Image) {
+	const margin, width, height, steps = 8, 120, 8, 60
+	face := DefaultFace()
+	lh := lineSpacing(face)
+	bounds := screen.Bounds()
+	x := float64(bounds.Max.X) - margin - width
+	y := float64(bounds.Max.Y) - margin - height - lh
+	vector.DrawFilledRect(screen, float32(x-2), float32(y-lh-2), width+4, float32(height+2*lh+4), color.RGBA{0, 0, 0, 0x80}, false)
+	drawText(screen, h.Quantity.String(), face, x, y-lh, color.White)
+	for i := 0; i < steps; i++ {
+		c := h.Color(h.Min + (h.Max-h.Min)*(float64(i)+0.5)/steps)
+		c.A = 0xFF
+		vector.DrawFilledRect(screen, float32(x)+float32(i)*width/steps, float32(y), width/steps+1, height, c, false)
+	}
+	maxText := fmt.Sprintf("%g", h.Max)
+	w, _ := measureText(maxText, face)
+	drawText(screen, fmt.Sprintf("%g", h.Min), face, x, y+height, color.White)
+	drawText(screen, maxText, face, x+width-w, y+height, color.White)
+}
@@ -0,0 +1,254 @@
+package ebitencp
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// History keeps the motion of the bodies of a space over the last steps
+// in a ring buffer so that they can be scrubbed back and forth.
+//
+// Each state is the bodies, shapes and constraints in the space and the
+// position, angle, velocity and angular velocity of every body that is not
+// static. Seeking puts the same objects back, so pointers and callbacks stay
+// valid: joints broken since a state are added again, and bodies, shapes and
+// constraints added since are removed. Cached contacts are not kept and are
+// found again on the next step.
+//
+//	// In Update(), after every step
+//	g.space.Step(1 / 60.0)
+//	g.history.Record()
+//
+//	// In Draw()
+//	g.history.DrawTimeline(screen)
+type History struct {
+	drawer *Drawer
+	space  *cp.Space
+	states []historyState
+	start  int
+	count  int
+	cursor int
+
+	timeline  image.Rectangle
+	scrubbing bool
+}
+
+// historyState is the space at one step.
+type historyState struct {
+	bodies      []bodyState
+	shapes      []*cp.Shape
+	constraints []*cp.Constraint
+}
+
+// bodyState is the motion of a body at one step.
+type bodyState struct {
+	body            *cp.Body
+	position        cp.Vector
+	angle           float64
+	velocity        cp.Vector
+	angularVelocity float64
+}
+
+// NewHistory keeps up to capacity states of space, which is dragged through d.
+// At 60 steps per second, a capacity of 600 is the last 10 seconds.
+// The current state of space is recorded as the first one.
+func NewHistory(d *Drawer, space *cp.Space, capacity int) (*History, error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("ebitencp: history capacity %d is less than 1", capacity)
+	}
+	h := &History{
+		drawer: d,
+		space:  space,
+		states: make([]historyState, capacity),
+		cursor: -1,
+	}
+	h.Record()
+	return h, nil
+}
+
+// Record saves the current state of the space. Call it after every step.
+// States after the current position are dropped first, so recording after
+// a seek resumes the history from there.
+func (h *History) Record() {
+	h.count = h.cursor + 1
+	if h.count == len(h.states) {
+		h.start = (h.start + 1) % len(h.states)
+		h.count--
+	}
+	// The slices of a dropped state are reused.
+	state := &h.states[(h.start+h.count)%len(h.states)]
+	state.bodies = state.bodies[:0]
+	state.shapes = state.shapes[:0]
+	state.constraints = state.constraints[:0]
+	h.space.EachBody(func(body *cp.Body) {
+		state.bodies = append(state.bodies, bodyState{
+			body:            body,
+			position:        body.Position(),
+			angle:           body.Angle(),
+			velocity:        body.Velocity(),
+			angularVelocity: body.AngularVelocity(),
+		})
+	})
+	h.space.EachShape(func(shape *cp.Shape) {
+		state.shapes = append(state.shapes, shape)
+	})
+	h.space.EachConstraint(func(c *cp.Constraint) {
+		// The joint that drags a body is released on Seek.
+		if c != h.drawer.handler.mouseJoint {
+			state.constraints = append(state.constraints, c)
+		}
+	})
+	h.cursor = h.count
+	h.count++
+}
+
+// Clear drops every state and records the current one as the first.
+func (h *History) Clear() {
+	h.start, h.count, h.cursor = 0, 0, -1
+	h.Record()
+}
+
+// Len returns the number of states kept.
+func (h *History) Len() int {
+	return h.count
+}
+
+// Position returns the index of the current state, 0 being the oldest.
+func (h *History) Position() int {
+	return h.cursor
+}
+
+// AtEnd reports whether the current state is the latest one.
+func (h *History) AtEnd() bool {
+	return h.cursor == h.count-1
+}
+
+// Seek restores the state at index i, clamped to the kept states.
+// Any body dragged through the drawer is released first.
+func (h *History) Seek(i int) {
+	i = max(0, min(i, h.count-1))
+	h.drawer.handler.reset()
+	state := &h.states[(h.start+i)%len(h.states)]
+
+	// Remove what was added since, constraints and shapes before their bodies.
+	bodies := make(map[*cp.Body]bool, len(state.bodies))
+	for _, s := range state.bodies {
+		bodies[s.body] = true
+	}
+	shapes := make(map[*cp.Shape]bool, len(state.shapes))
+	for _, shape := range state.shapes {
+		shapes[shape] = true
+	}
+	constraints := make(map[*cp.Constraint]bool, len(state.constraints))
+	for _, c := range state.constraints {
+		constraints[c] = true
+	}
+	var added []interface{}
+	h.space.EachConstraint(func(c *cp.Constraint) {
+		if !constraints[c] {
+			added = append(added, c)
+		}
+	})
+	h.space.EachShape(func(shape *cp.Shape) {
+		if !shapes[shape] {
+			added = append(added, shape)
+		}
+	})
+	h.space.EachBody(func(body *cp.Body) {
+		if !bodies[body] {
+			added = append(added, body)
+		}
+	})
+	for _, obj := range added {
+		switch obj := obj.(type) {
+		case *cp.Constraint:
+			h.space.RemoveConstraint(obj)
+		case *cp.Shape:
+			h.space.RemoveShape(obj)
+		case *cp.Body:
+			h.space.RemoveBody(obj)
+		}
+	}
+
+	// Add back what was removed since, bodies before their shapes and constraints.
+	for _, s := range state.bodies {
+		if !h.space.ContainsBody(s.body) {
+			h.space.AddBody(s.body)
+		}
+	}
+	for _, shape := range state.shapes {
+		if !h.space.ContainsShape(shape) {
+			h.space.AddShape(shape)
+		}
+	}
+	for _, c := range state.constraints {
+		if !h.space.ContainsConstraint(c) {
+			h.space.AddConstraint(c)
+		}
+	}
+
+	for _, s := range state.bodies {
+		if s.body.GetType() == cp.BODY_STATIC {
+			continue
+		}
+		// The angle goes first, because SetPosition places the center of gravity with it.
+		s.body.SetAngle(s.angle)
+		s.body.SetPosition(s.position)
+		s.body.SetVelocityVector(s.velocity)
+		s.body.SetAngularVelocity(s.angularVelocity)
+		reindexShapesForBody(h.space, s.body)
+	}
+	h.cursor = i
+}
+
+// handleTimeline returns the index under the pointer while the timeline drawn last is dragged.
+// ok reports whether the mouse is used by the timeline.
+func (h *History) handleTimeline() (i int, ok bool) {
+	if h.timeline.Empty() || h.count == 0 {
+		return 0, false
+	}
+	x, y := ebiten.CursorPosition()
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && image.Pt(x, y).In(h.timeline.Inset(-4)) {
+		h.scrubbing = true
+	}
+	if !h.scrubbing {
+		return 0, false
+	}
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		h.scrubbing = false
+		return h.cursor, true
+	}
+	return h.timelineIndex(x), true
+}
+
+// timelineIndex returns the index of the state at x on the timeline, clamped to the kept states.
+func (h *History) timelineIndex(x int) int {
+	// The recorded part of the bar grows with the history.
+	filled := float64(h.timeline.Dx()) * float64(h.count) / float64(len(h.states))
+	i := int(float64(x-h.timeline.Min.X)/filled*float64(h.count-1) + 0.5)
+	return max(0, min(i, h.count-1))
+}
+
+// DrawTimeline draws a bar along the bottom of screen with the current position in the history.
+// SimulationControl seeks when the bar is dragged.
+func (h *History) DrawTimeline(screen *ebiten.Image) {
+	const margin, height = 8, 6
+	bounds := screen.Bounds()
+	h.timeline = image.Rect(bounds.Min.X+margin, bounds.Max.Y-margin-height, bounds.Max.X-margin, bounds.Max.Y-margin)
+	x := float32(h.timeline.Min.X)
+	y := float32(h.timeline.Min.Y)
+	w := float32(h.timeline.Dx())
+	filled := w * float32(h.count) / float32(len(h.states))
+	vector.DrawFilledRect(screen, x, y, w, height, color.RGBA{0, 0, 0, 0x80}, false)
+	vector.DrawFilledRect(screen, x, y, filled, height, color.RGBA{0x80, 0x80, 0x80, 0xC0}, false)
+	if h.count > 1 {
+		cx := x + filled*float32(h.cursor)/float32(h.count-1)
+		vector.DrawFilledRect(screen, cx-1, y-2, 3, height+4, color.White, false)
+	}
+}
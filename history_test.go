@@ -0,0 +1,164 @@
+package ebitencp
+
+import (
+	"image"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// newTestHistory returns a history of a body moving one unit along X per step,
+// recorded for steps steps.
+func newTestHistory(t *testing.T, capacity, steps int) (*History, *cp.Space, *cp.Body) {
+	t.Helper()
+	space := cp.NewSpace()
+	body := addTestBox(space, cp.Vector{})
+	body.SetVelocity(1, 0)
+	h, err := NewHistory(&Drawer{}, space, capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < steps; i++ {
+		space.Step(1)
+		h.Record()
+	}
+	return h, space, body
+}
+
+func TestHistorySeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		steps    int
+		seek     int
+		wantLen  int
+		wantPos  int
+		wantX    float64
+	}{
+		{name: "first", capacity: 4, steps: 2, seek: 0, wantLen: 3, wantPos: 0, wantX: 0},
+		{name: "last", capacity: 4, steps: 2, seek: 2, wantLen: 3, wantPos: 2, wantX: 2},
+		{name: "wrapped first", capacity: 3, steps: 5, seek: 0, wantLen: 3, wantPos: 0, wantX: 3},
+		{name: "wrapped middle", capacity: 3, steps: 5, seek: 1, wantLen: 3, wantPos: 1, wantX: 4},
+		{name: "clamped below", capacity: 3, steps: 5, seek: -1, wantLen: 3, wantPos: 0, wantX: 3},
+		{name: "clamped above", capacity: 3, steps: 5, seek: 10, wantLen: 3, wantPos: 2, wantX: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, body := newTestHistory(t, tt.capacity, tt.steps)
+			if h.Len() != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", h.Len(), tt.wantLen)
+			}
+			h.Seek(tt.seek)
+			if h.Position() != tt.wantPos {
+				t.Errorf("Position() = %d, want %d", h.Position(), tt.wantPos)
+			}
+			if x := body.Position().X; x != tt.wantX {
+				t.Errorf("body at x = %g, want %g", x, tt.wantX)
+			}
+			if v := body.Velocity(); v != (cp.Vector{X: 1, Y: 0}) {
+				t.Errorf("body velocity = %v, want {1 0}", v)
+			}
+		})
+	}
+}
+
+func TestHistoryRecordAfterSeek(t *testing.T) {
+	h, space, body := newTestHistory(t, 10, 5)
+	h.Seek(2)
+	if h.AtEnd() {
+		t.Fatal("AtEnd() after seeking back")
+	}
+	space.Step(1)
+	h.Record()
+	if h.Len() != 4 || !h.AtEnd() {
+		t.Errorf("Len() = %d, AtEnd() = %v, want 4, true", h.Len(), h.AtEnd())
+	}
+	if x := body.Position().X; x != 3 {
+		t.Errorf("body at x = %g, want 3", x)
+	}
+}
+
+func TestHistoryTimelineIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		steps    int
+		x        int
+		want     int
+	}{
+		{name: "full start", capacity: 10, steps: 9, x: 0, want: 0},
+		{name: "full end", capacity: 10, steps: 9, x: 100, want: 9},
+		{name: "full middle", capacity: 10, steps: 9, x: 50, want: 5},
+		{name: "left of the bar", capacity: 10, steps: 9, x: -20, want: 0},
+		{name: "right of the bar", capacity: 10, steps: 9, x: 200, want: 9},
+		{name: "half filled end", capacity: 10, steps: 4, x: 50, want: 4},
+		{name: "half filled middle", capacity: 10, steps: 4, x: 25, want: 2},
+		{name: "past the recorded part", capacity: 10, steps: 4, x: 90, want: 4},
+		{name: "one state", capacity: 10, steps: 0, x: 50, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newTestHistory(t, tt.capacity, tt.steps)
+			h.timeline = image.Rect(0, 0, 100, 8)
+			if got := h.timelineIndex(tt.x); got != tt.want {
+				t.Errorf("timelineIndex(%d) = %d, want %d", tt.x, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryTimelineBeforeDraw(t *testing.T) {
+	h, _, _ := newTestHistory(t, 10, 3)
+	if _, ok := h.handleTimeline(); ok {
+		t.Error("handleTimeline() used the mouse before the timeline was drawn")
+	}
+}
+
+func TestHistorySeekRestoresObjects(t *testing.T) {
+	h, space, body := newTestHistory(t, 10, 0)
+	other := addTestBox(space, cp.Vector{X: 5, Y: 0})
+	joint := space.AddConstraint(cp.NewPinJoint(body, other, cp.Vector{}, cp.Vector{}))
+	broken := 0
+	joint.PostSolve = func(c *cp.Constraint, space *cp.Space) { broken++ }
+	space.Step(1)
+	h.Record()
+
+	// Break the joint and add a body after the recorded state.
+	space.RemoveConstraint(joint)
+	added := addTestBox(space, cp.Vector{X: 20, Y: 0})
+	space.Step(1)
+	h.Record()
+
+	h.Seek(1)
+	if !space.ContainsConstraint(joint) {
+		t.Error("the broken joint was not added back")
+	}
+	if space.ContainsBody(added) {
+		t.Error("the body added later is still in the space")
+	}
+	var shapes int
+	added.EachShape(func(shape *cp.Shape) {
+		if space.ContainsShape(shape) {
+			shapes++
+		}
+	})
+	if shapes != 0 {
+		t.Errorf("%d shapes of the body added later are still in the space", shapes)
+	}
+	if !space.ContainsBody(body) || !space.ContainsBody(other) {
+		t.Error("a recorded body is missing")
+	}
+	before := broken
+	space.Step(1)
+	if broken == before {
+		t.Error("the callback of the joint was lost")
+	}
+
+	h.Seek(0)
+	if space.ContainsConstraint(joint) {
+		t.Error("the joint added after the first state is still in the space")
+	}
+	if space.ContainsBody(other) {
+		t.Error("the body added after the first state is still in the space")
+	}
+}
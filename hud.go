@@ -0,0 +1,172 @@
+package ebitencp
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// HUDSection is a set of statistics shown by the HUD.
+type HUDSection uint
+
+const (
+	// FPS and TPS
+	HUDFPS HUDSection = 1 << iota
+	// Dynamic, static, kinematic and sleeping body counts
+	HUDBodies
+	// Shape and constraint counts
+	HUDShapes
+	// Active arbiters
+	HUDArbiters
+	// Time spent in space.Step
+	HUDStepTime
+	// Triangles and draw calls of the drawer
+	HUDDrawStats
+	// State of the SimulationControl
+	HUDControl
+
+	HUDAll = HUDFPS | HUDBodies | HUDShapes | HUDArbiters | HUDStepTime | HUDDrawStats | HUDControl
+)
+
+// HUDPlacement is the screen corner the HUD is drawn in.
+type HUDPlacement int
+
+const (
+	HUDTopLeft HUDPlacement = iota
+	HUDTopRight
+	HUDBottomLeft
+	HUDBottomRight
+)
+
+// HUD is an on-screen overlay with physics statistics.
+//
+//	func (g *Game) Update() error {
+//		g.hud.Step(g.space, 1/60.0)
+//		return nil
+//	}
+//	func (g *Game) Draw(screen *ebiten.Image) {
+//		cp.DrawSpace(g.space, g.drawer.WithScreen(screen))
+//		g.hud.Draw(screen, g.space, g.drawer)
+//	}
+type HUD struct {
+	Sections  HUDSection
+	Placement HUDPlacement
+	// Margin is the distance from the screen edges in pixels.
+	Margin     float64
+	Face       text.Face
+	Color      color.Color
+	Background color.Color
+	// Control is shown when it is not nil.
+	Control *SimulationControl
+
+	stepTime  time.Duration
+	stepCount int
+	lastTime  time.Duration
+	lastCount int
+}
+
+func NewHUD() *HUD {
+	return &HUD{
+		Sections:   HUDAll,
+		Placement:  HUDTopLeft,
+		Margin:     4,
+		Face:       DefaultFace(),
+		Color:      color.White,
+		Background: color.RGBA{0, 0, 0, 0x80},
+	}
+}
+
+// Step steps space and measures the time it takes.
+func (h *HUD) Step(space *cp.Space, dt float64) {
+	start := time.Now()
+	space.Step(dt)
+	h.stepTime += time.Since(start)
+	h.stepCount++
+}
+
+// Text returns the statistics shown by the HUD.
+// d may be nil, in which case draw statistics are left out.
+func (h *HUD) Text(space *cp.Space, d *Drawer) string {
+	var lines []string
+	if h.Sections&HUDControl != 0 && h.Control != nil {
+		lines = append(lines, h.Control.String())
+	}
+	if h.Sections&HUDFPS != 0 {
+		lines = append(lines, fmt.Sprintf("FPS: %0.2f  TPS: %0.2f", ebiten.ActualFPS(), ebiten.ActualTPS()))
+	}
+	if h.Sections&HUDBodies != 0 {
+		var dynamic, static, kinematic, sleeping int
+		space.EachBody(func(body *cp.Body) {
+			switch body.GetType() {
+			case cp.BODY_DYNAMIC:
+				dynamic++
+			case cp.BODY_STATIC:
+				static++
+			case cp.BODY_KINEMATIC:
+				kinematic++
+			}
+			if body.IsSleeping() {
+				sleeping++
+			}
+		})
+		lines = append(lines, fmt.Sprintf("Bodies: %d dynamic, %d static, %d kinematic, %d sleeping", dynamic, static, kinematic, sleeping))
+	}
+	if h.Sections&HUDShapes != 0 {
+		var shapes, constraints int
+		space.EachShape(func(*cp.Shape) { shapes++ })
+		space.EachConstraint(func(*cp.Constraint) { constraints++ })
+		lines = append(lines, fmt.Sprintf("Shapes: %d  Constraints: %d", shapes, constraints))
+	}
+	if h.Sections&HUDArbiters != 0 {
+		lines = append(lines, fmt.Sprintf("Arbiters: %d", len(spaceArbiters(space))))
+	}
+	if h.Sections&HUDStepTime != 0 {
+		lines = append(lines, fmt.Sprintf("Step: %0.3f ms (%d steps)", float64(h.lastTime.Microseconds())/1000, h.lastCount))
+	}
+	if h.Sections&HUDDrawStats != 0 && d != nil {
+		stats := d.DrawStats()
+		lines = append(lines, fmt.Sprintf("Triangles: %d  Draw calls: %d", stats.Triangles, stats.DrawCalls))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Draw draws the HUD on screen. Call it once per frame after drawing the space with d.
+// The step time shown is the total of the Step calls since the previous Draw.
+// The draw statistics of d are reset.
+func (h *HUD) Draw(screen *ebiten.Image, space *cp.Space, d *Drawer) {
+	if h.stepCount > 0 {
+		h.lastTime, h.lastCount = h.stepTime, h.stepCount
+		h.stepTime, h.stepCount = 0, 0
+	}
+	str := h.Text(space, d)
+	if d != nil {
+		d.ResetDrawStats()
+	}
+	if str == "" {
+		return
+	}
+
+	const padding = 2
+	w, hh := measureText(str, h.Face)
+	w += padding * 2
+	hh += padding * 2
+	bounds := screen.Bounds()
+	x := float64(bounds.Min.X) + h.Margin
+	y := float64(bounds.Min.Y) + h.Margin
+	if h.Placement == HUDTopRight || h.Placement == HUDBottomRight {
+		x = float64(bounds.Max.X) - h.Margin - w
+	}
+	if h.Placement == HUDBottomLeft || h.Placement == HUDBottomRight {
+		y = float64(bounds.Max.Y) - h.Margin - hh
+	}
+	if h.Background != nil {
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(hh), h.Background, false)
+	}
+	drawText(screen, str, h.Face, x+padding, y+padding, h.Color)
+}
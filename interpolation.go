@@ -0,0 +1,77 @@
+package ebitencp
+
+import (
+	"github.com/jakecoffman/cp/v2"
+)
+
+type bodyTransform struct {
+	position cp.Vector
+	angle    float64
+	// Cleared by space.Step, kept for MotionOverlay
+	force  cp.Vector
+	torque float64
+}
+
+// SaveTransforms remembers the position and angle of every body in space.
+// Call it before each space.Step, then set Alpha before DrawSpace to draw
+// the bodies between the saved and the current transforms.
+// The forces and torques about to be applied are saved for MotionOverlay too,
+// and TrailOverlay records its samples here.
+func (d *Drawer) SaveTransforms(space *cp.Space) {
+	if d.previous == nil {
+		d.previous = map[*cp.Body]bodyTransform{}
+	}
+	clear(d.previous)
+	space.EachBody(func(body *cp.Body) {
+		d.previous[body] = bodyTransform{body.Position(), body.Angle(), body.Force(), body.Torque()}
+	})
+	if d.Trails != nil {
+		d.Trails.record(space)
+	}
+}
+
+// bodyTransform returns the transform a body is drawn with.
+func (d *Drawer) bodyTransform(body *cp.Body) (t cp.Transform, angle float64) {
+	current := bodyTransform{position: body.Position(), angle: body.Angle()}
+	if previous, ok := d.previous[body]; ok && d.Alpha < 1 {
+		current.position = previous.position.Lerp(current.position, d.Alpha)
+		current.angle = cp.Lerp(previous.angle, current.angle, d.Alpha)
+	}
+	return cp.NewTransformRigid(current.position, current.angle), current.angle
+}
+
+// blendedPoint moves the world point p with body to the transform it is drawn with.
+func (d *Drawer) blendedPoint(body *cp.Body, p cp.Vector) cp.Vector {
+	t, _ := d.bodyTransform(body)
+	return t.Point(body.WorldToLocal(p))
+}
+
+// drawShape draws shape like cp.DrawShape, at the transform blended by Alpha.
+func (d *Drawer) drawShape(shape *cp.Shape) {
+	t, angle := d.bodyTransform(shape.Body())
+	d.beginTexture(shape, t)
+	defer d.endTexture()
+	if _, ok := d.previous[shape.Body()]; !ok || d.Alpha >= 1 {
+		cp.DrawShape(shape, d)
+		return
+	}
+
+	d.drawShapeAt(shape, t, angle, d.OutlineColor(), d.ShapeColor(shape, nil))
+}
+
+// drawShapeAt draws shape as if its body had the transform t and angle.
+func (d *Drawer) drawShapeAt(shape *cp.Shape, t cp.Transform, angle float64, outline, fill cp.FColor) {
+	switch class := shape.Class.(type) {
+	case *cp.Circle:
+		d.DrawCircle(t.Point(circleOffset(class)), angle, class.Radius(), outline, fill, nil)
+	case *cp.Segment:
+		d.DrawFatSegment(t.Point(class.A()), t.Point(class.B()), class.Radius(), outline, fill, nil)
+	case *cp.PolyShape:
+		count := class.Count()
+		verts := make([]cp.Vector, count)
+		for i := range verts {
+			verts[i] = t.Point(class.Vert(i))
+		}
+		d.DrawPolygon(count, verts, class.Radius(), outline, fill, nil)
+	}
+}
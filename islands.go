@@ -0,0 +1,111 @@
+package ebitencp
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// IslandOverlay colors the shapes of each island, the dynamic bodies connected
+// by contacts or constraints, with its own hue. Set it to Drawer.Islands.
+//
+// The color darkens as the idle time of the body approaches the
+// SleepTimeThreshold of the space, and sleeping islands keep their hue.
+// Islands are found by DrawSpace; cp.DrawSpace draws the usual colors.
+type IslandOverlay struct {
+	// Links draws a line from each body to the first body of its island.
+	Links bool
+}
+
+func NewIslandOverlay() *IslandOverlay {
+	return &IslandOverlay{}
+}
+
+type island struct {
+	root *cp.Body
+	hue  float64
+}
+
+// findIslands groups the dynamic bodies of space the way cp does before it puts them to sleep.
+func (d *Drawer) findIslands(space *cp.Space) {
+	parent := map[*cp.Body]*cp.Body{}
+	var order []*cp.Body
+	var find func(body *cp.Body) *cp.Body
+	find = func(body *cp.Body) *cp.Body {
+		if p := parent[body]; p != body {
+			parent[body] = find(p)
+		}
+		return parent[body]
+	}
+	union := func(a, b *cp.Body) {
+		if _, ok := parent[a]; !ok {
+			return
+		}
+		if _, ok := parent[b]; !ok {
+			return
+		}
+		parent[find(b)] = find(a)
+	}
+
+	space.EachBody(func(body *cp.Body) {
+		if body.GetType() == cp.BODY_DYNAMIC {
+			parent[body] = body
+			order = append(order, body)
+		}
+	})
+	// Only sleeping bodies keep the root of their component.
+	for _, body := range order {
+		if root := body.ComponentRoot(); root != nil {
+			union(root, body)
+		}
+	}
+	for _, arb := range spaceArbiters(space) {
+		union(arb.Bodies())
+	}
+	space.EachConstraint(func(c *cp.Constraint) {
+		union(constraintBodies(c))
+	})
+
+	if d.islands == nil {
+		d.islands = map[*cp.Body]island{}
+	}
+	clear(d.islands)
+	first := map[*cp.Body]island{}
+	for i, body := range order {
+		root := find(body)
+		is, ok := first[root]
+		if !ok {
+			// Spread the hues of neighboring indices with the golden ratio.
+			_, hue := math.Modf(float64(i) * 0.618033988749895)
+			is = island{root: body, hue: hue}
+			first[root] = is
+		}
+		d.islands[body] = is
+	}
+}
+
+// islandColor returns the color of shape, or false if its body is in no island.
+func (d *Drawer) islandColor(shape *cp.Shape) (cp.FColor, bool) {
+	body := shape.Body()
+	is, ok := d.islands[body]
+	if !ok {
+		return cp.FColor{}, false
+	}
+	idle := 1.0
+	if threshold := shape.Space().SleepTimeThreshold; !body.IsSleeping() && threshold > 0 {
+		idle = cp.Clamp01(body.IdleTime() / threshold)
+	}
+	return hsv(is.hue, 0.6, 1-0.6*idle, float64(d.Theme.Shape.A)/255), true
+}
+
+func (d *Drawer) drawIslandLinks() {
+	clr := toFColor(d.Theme.IslandLink)
+	for body, is := range d.islands {
+		if body == is.root {
+			continue
+		}
+		a, _ := d.bodyTransform(is.root)
+		b, _ := d.bodyTransform(body)
+		d.DrawSegment(a.Point(is.root.CenterOfGravity()), b.Point(body.CenterOfGravity()), clr, nil)
+	}
+}
@@ -0,0 +1,191 @@
+package ebitencp
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Sizes of the joint decorations in pixels
+const (
+	jointCapRadius  = 3.0
+	jointArcRadius  = 12.0
+	springWidth     = 4.0
+	springZigzags   = 12
+	springLeadShare = 0.1
+)
+
+// drawConstraint draws c at the transforms blended by Alpha, in the Theme color of its type.
+func (d *Drawer) drawConstraint(c *cp.Constraint, dt float64) {
+	bodyA, bodyB := constraintBodies(c)
+	ta, angleA := d.bodyTransform(bodyA)
+	tb, angleB := d.bodyTransform(bodyB)
+	cogA := ta.Point(bodyA.CenterOfGravity())
+	cogB := tb.Point(bodyB.CenterOfGravity())
+	// Angles are drawn around the body that is not static.
+	center := cogB
+	if bodyB.GetType() == cp.BODY_STATIC {
+		center = cogA
+	}
+	clr := d.jointColor(c, dt)
+
+	switch j := c.Class.(type) {
+	case *cp.PinJoint:
+		a, b := ta.Point(j.AnchorA), tb.Point(j.AnchorB)
+		d.DrawSegment(a, b, clr, nil)
+		d.drawRing(a, d.pixels(jointCapRadius), clr)
+		d.drawRing(b, d.pixels(jointCapRadius), clr)
+	case *cp.SlideJoint:
+		a, b := ta.Point(j.AnchorA), tb.Point(j.AnchorB)
+		d.DrawDot(2*jointCapRadius, a, clr, nil)
+		d.DrawDot(2*jointCapRadius, b, clr, nil)
+		d.DrawSegment(a, b, clr, nil)
+	case *cp.PivotJoint:
+		d.drawRing(ta.Point(j.AnchorA), d.pixels(jointCapRadius), clr)
+		d.DrawDot(2*jointCapRadius, tb.Point(j.AnchorB), clr, nil)
+	case *cp.GrooveJoint:
+		d.drawSlot(ta.Point(j.GrooveA), ta.Point(j.GrooveB), d.pixels(jointCapRadius), clr)
+		d.DrawDot(2*jointCapRadius, tb.Point(j.AnchorB), clr, nil)
+	case *cp.DampedSpring:
+		a, b := ta.Point(j.AnchorA), tb.Point(j.AnchorB)
+		d.drawSpring(a, b, clr)
+		d.DrawDot(2*jointCapRadius, a, clr, nil)
+		d.DrawDot(2*jointCapRadius, b, clr, nil)
+	case *cp.DampedRotarySpring:
+		// The arc is how far the spring is wound from its rest angle.
+		d.drawArc(center, d.pixels(jointArcRadius), angleA+j.RestAngle, angleB-angleA-j.RestAngle, clr)
+		d.drawSpoke(center, d.pixels(jointArcRadius), angleB, clr)
+	case *cp.RotaryLimitJoint:
+		d.drawArc(center, d.pixels(jointArcRadius), angleA+j.Min, j.Max-j.Min, clr)
+		d.drawSpoke(center, d.pixels(jointArcRadius), angleB, clr)
+	case *cp.RatchetJoint:
+		d.drawRatchet(center, angleA+j.Angle, j.Ratchet, clr)
+		d.drawSpoke(center, d.pixels(jointArcRadius), angleB, clr)
+	case *cp.GearJoint:
+		d.drawGear(cogA, cogB, angleA, angleB, j, clr)
+	case *cp.SimpleMotor:
+		// The arrow turns with the body, in the direction of the rate.
+		sweep := math.Copysign(1.5*math.Pi, j.Rate)
+		if j.Rate == 0 {
+			d.drawRing(center, d.pixels(jointArcRadius), clr)
+			break
+		}
+		d.drawArcArrow(center, d.pixels(jointArcRadius), angleB, sweep, clr)
+	default:
+		d.DrawSegment(cogA, cogB, clr, nil)
+	}
+}
+
+// jointColor returns the color of c, by type or by stress with StressColors.
+func (d *Drawer) jointColor(c *cp.Constraint, dt float64) cp.FColor {
+	if d.StressColors {
+		return d.stressColor(c, dt)
+	}
+	var clr color.RGBA
+	switch c.Class.(type) {
+	case *cp.PinJoint:
+		clr = d.Theme.PinJoint
+	case *cp.SlideJoint:
+		clr = d.Theme.SlideJoint
+	case *cp.PivotJoint:
+		clr = d.Theme.PivotJoint
+	case *cp.GrooveJoint:
+		clr = d.Theme.GrooveJoint
+	case *cp.DampedSpring:
+		clr = d.Theme.DampedSpring
+	case *cp.DampedRotarySpring:
+		clr = d.Theme.DampedRotarySpring
+	case *cp.RotaryLimitJoint:
+		clr = d.Theme.RotaryLimitJoint
+	case *cp.RatchetJoint:
+		clr = d.Theme.RatchetJoint
+	case *cp.GearJoint:
+		clr = d.Theme.GearJoint
+	case *cp.SimpleMotor:
+		clr = d.Theme.SimpleMotor
+	}
+	if clr == (color.RGBA{}) {
+		return d.ConstraintColor()
+	}
+	return toFColor(clr)
+}
+
+// drawSpring draws a zigzag from a to b. The zigzags keep their width,
+// so they bunch up as the spring compresses and spread as it stretches.
+func (d *Drawer) drawSpring(a, b cp.Vector, clr cp.FColor) {
+	delta := b.Sub(a)
+	if delta.LengthSq() == 0 {
+		return
+	}
+	side := delta.Normalize().Perp().Mult(d.pixels(springWidth))
+	path := d.newPath()
+	path.MoveTo(a)
+	for i := 0; i <= springZigzags; i++ {
+		t := springLeadShare + (1-2*springLeadShare)*float64(i)/springZigzags
+		p := a.Add(delta.Mult(t))
+		if i > 0 && i < springZigzags {
+			if i%2 == 0 {
+				p = p.Sub(side)
+			} else {
+				p = p.Add(side)
+			}
+		}
+		path.LineTo(p)
+	}
+	path.LineTo(b)
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
+
+// drawSlot draws the outline of a slot of radius around the segment from a to b.
+func (d *Drawer) drawSlot(a, b cp.Vector, radius float64, clr cp.FColor) {
+	t1 := math.Atan2(b.Y-a.Y, b.X-a.X) + math.Pi/2
+	t2 := t1 + math.Pi
+	path := d.newPath()
+	path.Arc(a, radius, t1, t1+math.Pi, vector.Clockwise)
+	path.Arc(b, radius, t2, t2+math.Pi, vector.Clockwise)
+	path.Close()
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
+
+// drawRatchet draws the teeth of a ratchet around center, next to the one at angle.
+func (d *Drawer) drawRatchet(center cp.Vector, angle, ratchet float64, clr cp.FColor) {
+	const teeth = 2
+	d.drawArc(center, d.pixels(jointArcRadius), angle-teeth*ratchet, 2*teeth*ratchet, clr)
+	path := d.newPath()
+	for i := -teeth; i <= teeth; i++ {
+		r := cp.ForAngle(angle + float64(i)*ratchet)
+		inner := center.Add(r.Mult(d.pixels(jointArcRadius)))
+		outer := center.Add(r.Mult(d.pixels(jointArcRadius + jointCapRadius)))
+		path.MoveTo(inner)
+		path.LineTo(outer)
+	}
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
+
+// drawGear draws the bodies of a gear joint as two linked gears sized by the ratio,
+// each with a spoke at its angle. The arc on the gear of A is the phase.
+func (d *Drawer) drawGear(cogA, cogB cp.Vector, angleA, angleB float64, j *cp.GearJoint, clr cp.FColor) {
+	phase, ratio := gearJointParams(j)
+	// B turns 1/ratio times as fast as A, so its gear is ratio times as large.
+	// The larger one gets the arc radius.
+	scale := d.pixels(jointArcRadius) / math.Max(1, math.Abs(ratio))
+	radiusA := math.Max(scale, d.pixels(jointCapRadius))
+	radiusB := math.Max(scale*math.Abs(ratio), d.pixels(jointCapRadius))
+	if cogA.Distance(cogB) > radiusA+radiusB {
+		// The link runs between the rims.
+		dir := cogB.Sub(cogA).Normalize()
+		d.DrawSegment(cogA.Add(dir.Mult(radiusA)), cogB.Sub(dir.Mult(radiusB)), clr, nil)
+	}
+	d.drawRing(cogA, radiusA, clr)
+	d.drawRing(cogB, radiusB, clr)
+	d.drawSpoke(cogA, radiusA, angleA, clr)
+	d.drawSpoke(cogB, radiusB, angleB, clr)
+	d.drawArc(cogA, radiusA+d.pixels(jointCapRadius), angleA, phase, clr)
+}
+
+// drawSpoke draws a line from center to radius at angle.
+func (d *Drawer) drawSpoke(center cp.Vector, radius, angle float64, clr cp.FColor) {
+	d.DrawSegment(center, center.Add(cp.ForAngle(angle).Mult(radius)), clr, nil)
+}
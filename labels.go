@@ -0,0 +1,71 @@
+package ebitencp
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// LabelOverlay draws text at the position of each body. Set it to Drawer.Labels.
+//
+// Labels are drawn in screen space, so Face keeps its size at any zoom.
+// A label overlapping one drawn before it is left out.
+//
+//	g.drawer.Labels = ebitencp.NewLabelOverlay(func(body *cp.Body) string {
+//		return fmt.Sprintf("%.1f", body.Velocity().Length())
+//	})
+type LabelOverlay struct {
+	// Labeler returns the label of a body. Bodies with an empty label are skipped.
+	Labeler    func(body *cp.Body) string
+	Face       text.Face
+	Color      color.Color
+	Background color.Color
+	// AllowOverlap draws every label even when they overlap.
+	AllowOverlap bool
+}
+
+func NewLabelOverlay(labeler func(body *cp.Body) string) *LabelOverlay {
+	return &LabelOverlay{
+		Labeler:    labeler,
+		Face:       DefaultFace(),
+		Color:      color.White,
+		Background: color.RGBA{0, 0, 0, 0x80},
+	}
+}
+
+func (d *Drawer) drawLabels(space *cp.Space) {
+	l := d.Labels
+	bounds := d.Screen.Bounds()
+	var drawn []image.Rectangle
+	space.EachBody(func(body *cp.Body) {
+		str := l.Labeler(body)
+		if str == "" {
+			return
+		}
+		t, _ := d.bodyTransform(body)
+		p := d.WorldToScreen(t.Point(cp.Vector{}))
+		const padding = 1
+		w, h := measureText(str, l.Face)
+		x := p.X - w/2
+		y := p.Y - h/2
+		rect := image.Rect(int(x)-padding, int(y)-padding, int(x+w)+padding, int(y+h)+padding)
+		if !rect.Overlaps(bounds) {
+			return
+		}
+		if !l.AllowOverlap {
+			for _, r := range drawn {
+				if rect.Overlaps(r) {
+					return
+				}
+			}
+			drawn = append(drawn, rect)
+		}
+		if l.Background != nil {
+			vector.DrawFilledRect(d.Screen, float32(rect.Min.X), float32(rect.Min.Y), float32(rect.Dx()), float32(rect.Dy()), l.Background, false)
+		}
+		drawText(d.Screen, str, l.Face, x, y, l.Color)
+	})
+}
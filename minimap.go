@@ -0,0 +1,165 @@
+package ebitencp
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Minimap draws the whole space small into a corner of the screen,
+// with the part seen through the camera of a drawer outlined.
+//
+// Shapes are drawn in flat colors into an image that is only redrawn every
+// Interval frames, so large spaces stay cheap. The outline follows the camera every frame.
+//
+//	// In Update()
+//	if p, ok := g.minimap.HandleMouseEvent(); ok {
+//		g.cameraTarget = p
+//	} else {
+//		g.drawer.HandleMouseEvent(g.space)
+//	}
+//
+//	// In Draw()
+//	g.drawer.WithScreen(screen).DrawSpace(g.space)
+//	g.minimap.Draw(screen, g.space)
+type Minimap struct {
+	// Rect is where the minimap is drawn on the screen.
+	Rect image.Rectangle
+	// Extent is the part of the world shown. When it is empty,
+	// the union of the bounding boxes of the shapes is used.
+	Extent cp.BB
+	// Interval is the number of frames between redraws of the shapes.
+	Interval int
+
+	drawer   *Drawer
+	view     *Drawer
+	image    *ebiten.Image
+	frames   int
+	dragging bool
+}
+
+// NewMinimap draws the space into rect on the screen with the camera of d outlined.
+func NewMinimap(d *Drawer, rect image.Rectangle) *Minimap {
+	view := NewDrawer(rect.Dx(), rect.Dy())
+	view.Theme = d.Theme
+	view.FlipYAxis = d.FlipYAxis
+	return &Minimap{
+		Rect:     rect,
+		Interval: 10,
+		drawer:   d,
+		view:     view,
+	}
+}
+
+// Refresh redraws the shapes on the next Draw.
+func (m *Minimap) Refresh() {
+	m.frames = 0
+}
+
+// extent returns the part of the world shown, with a margin around the shapes.
+func (m *Minimap) extent(space *cp.Space) (cp.BB, bool) {
+	if m.Extent.R > m.Extent.L && m.Extent.T > m.Extent.B {
+		return m.Extent, true
+	}
+	bb := cp.BB{L: math.Inf(1), B: math.Inf(1), R: math.Inf(-1), T: math.Inf(-1)}
+	space.EachShape(func(shape *cp.Shape) {
+		bb = bb.Merge(shape.BB())
+	})
+	if bb.L > bb.R {
+		return bb, false
+	}
+	margin := math.Max(bb.R-bb.L, bb.T-bb.B) * 0.05
+	return bb.Expand(cp.Vector{X: bb.L - margin, Y: bb.B - margin}).Expand(cp.Vector{X: bb.R + margin, Y: bb.T + margin}), true
+}
+
+// fit points the camera of the view at bb, scaled to fit in Rect.
+func (m *Minimap) fit(bb cp.BB) {
+	w, h := bb.R-bb.L, bb.T-bb.B
+	scale := math.Min(float64(m.Rect.Dx())/math.Max(w, 1e-9), float64(m.Rect.Dy())/math.Max(h, 1e-9))
+	m.view.FlipYAxis = m.drawer.FlipYAxis
+	m.view.GeoM.Reset()
+	m.view.GeoM.Translate(-bb.Center().X, -bb.Center().Y)
+	m.view.GeoM.Scale(scale, scale)
+}
+
+func (m *Minimap) redraw(space *cp.Space) {
+	bb, ok := m.extent(space)
+	if !ok {
+		return
+	}
+	m.fit(bb)
+	if m.image == nil || m.image.Bounds().Size() != m.Rect.Size() {
+		m.image = ebiten.NewImage(m.Rect.Dx(), m.Rect.Dy())
+	}
+	m.image.Fill(m.drawer.Theme.MinimapBackground)
+	m.view.WithScreen(m.image)
+	space.EachShape(func(shape *cp.Shape) {
+		clr := m.drawer.ShapeColor(shape, nil)
+		clr.A = 1
+		t, angle := m.view.bodyTransform(shape.Body())
+		m.view.drawShapeAt(shape, t, angle, clr, clr)
+	})
+}
+
+// Draw draws the minimap on screen. The shapes are redrawn every Interval frames.
+func (m *Minimap) Draw(screen *ebiten.Image, space *cp.Space) {
+	if m.frames <= 0 || m.image == nil {
+		m.redraw(space)
+		m.frames = max(m.Interval, 1)
+	}
+	m.frames--
+	if m.image == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(m.Rect.Min.X), float64(m.Rect.Min.Y))
+	screen.DrawImage(m.image, op)
+
+	// The corners of the viewport of the drawer, which may be rotated.
+	// The outline is stroked in screen space, because the view scales world units far below a pixel.
+	m.view.WithScreen(screen.SubImage(m.Rect).(*ebiten.Image))
+	v := m.drawer.viewport()
+	path := vector.Path{}
+	for i, p := range []image.Point{v.Min, {X: v.Max.X, Y: v.Min.Y}, v.Max, {X: v.Min.X, Y: v.Max.Y}} {
+		w := m.drawer.ScreenToWorld(cp.Vector{X: float64(p.X), Y: float64(p.Y)})
+		s := m.view.WorldToScreen(w)
+		if i == 0 {
+			path.MoveTo(float32(s.X), float32(s.Y))
+		} else {
+			path.LineTo(float32(s.X), float32(s.Y))
+		}
+	}
+	path.Close()
+	sop := &vector.StrokeOptions{}
+	sop.Width = m.view.StrokeWidth
+	sop.LineJoin = vector.LineJoinRound
+	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, sop)
+	clr := toFColor(m.drawer.Theme.MinimapFrustum)
+	applyMatrixToVertices(vs, ebiten.GeoM{}, clr.R, clr.G, clr.B, clr.A)
+	m.view.Screen.DrawTriangles(vs, is, m.view.whiteImage, m.view.OptStroke)
+}
+
+// HandleMouseEvent returns the world point under the pointer while the minimap is dragged,
+// for the camera to jump to. ok reports whether the mouse is used by the minimap,
+// in which case the drawer should not handle it.
+func (m *Minimap) HandleMouseEvent() (p cp.Vector, ok bool) {
+	x, y := ebiten.CursorPosition()
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && image.Pt(x, y).In(m.Rect) {
+		m.dragging = true
+	}
+	if !m.dragging {
+		return cp.Vector{}, false
+	}
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		m.dragging = false
+	}
+	// The view draws into the sub-image of Rect after the first Draw.
+	if m.view.Screen == nil {
+		return cp.Vector{}, true
+	}
+	return m.view.ScreenToWorld(cp.Vector{X: float64(x), Y: float64(y)}), true
+}
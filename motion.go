@@ -0,0 +1,97 @@
+package ebitencp
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Motion is a set of quantities drawn by MotionOverlay.
+type Motion uint
+
+const (
+	// Linear velocity as an arrow from the center of gravity
+	MotionVelocity Motion = 1 << iota
+	// Angular velocity as an arc around the center of gravity
+	MotionAngularVelocity
+	// Center of gravity as a crossed circle
+	MotionCenterOfGravity
+	// Force applied for the step as an arrow from the center of gravity.
+	// Gravity and contacts are not included.
+	MotionForce
+	// Torque applied for the step as an arc around the center of gravity
+	MotionTorque
+
+	MotionAll = MotionVelocity | MotionAngularVelocity | MotionCenterOfGravity | MotionForce | MotionTorque
+)
+
+// MotionOverlay draws how bodies move over the space. Set it to Drawer.Motion.
+//
+// cp clears forces and torques in space.Step, so the ones saved by
+// Drawer.SaveTransforms before the step are drawn when there are any.
+type MotionOverlay struct {
+	Show Motion
+	// VelocityScale is the arrow length per unit of velocity.
+	// 0.25 draws where the body will be in a quarter of a second.
+	VelocityScale float64
+	// AngularVelocityScale is the arc length in radians per radian per second.
+	AngularVelocityScale float64
+	// ForceScale is the arrow length per unit of acceleration, that is force divided by mass.
+	ForceScale float64
+	// TorqueScale is the arc length in radians per unit of angular acceleration,
+	// that is torque divided by moment.
+	TorqueScale float64
+	// MarkerSize is the radius of the center of gravity marker in pixels.
+	// The arcs are drawn at two and three times it.
+	MarkerSize float64
+	// Filter limits the overlay to the bodies it returns true for.
+	// When it is nil, every body but static ones is drawn.
+	Filter func(body *cp.Body) bool
+}
+
+func NewMotionOverlay() *MotionOverlay {
+	return &MotionOverlay{
+		Show:                 MotionAll,
+		VelocityScale:        0.25,
+		AngularVelocityScale: 0.25,
+		ForceScale:           0.25,
+		TorqueScale:          0.05,
+		MarkerSize:           4,
+	}
+}
+
+func (d *Drawer) drawMotion(space *cp.Space) {
+	m := d.Motion
+	space.EachBody(func(body *cp.Body) {
+		if m.Filter != nil && !m.Filter(body) || m.Filter == nil && body.GetType() == cp.BODY_STATIC {
+			return
+		}
+		t, angle := d.bodyTransform(body)
+		cog := t.Point(body.CenterOfGravity())
+
+		if m.Show&MotionCenterOfGravity != 0 {
+			d.drawCross(cog, d.pixels(m.MarkerSize), toFColor(d.Theme.CenterOfGravity))
+		}
+		if m.Show&MotionVelocity != 0 {
+			d.drawArrow(cog, cog.Add(body.Velocity().Mult(m.VelocityScale)), toFColor(d.Theme.Velocity))
+		}
+		if m.Show&MotionAngularVelocity != 0 {
+			d.drawArcArrow(cog, d.pixels(m.MarkerSize*2), angle, body.AngularVelocity()*m.AngularVelocityScale, toFColor(d.Theme.AngularVelocity))
+		}
+		if m.Show&(MotionForce|MotionTorque) == 0 || body.GetType() != cp.BODY_DYNAMIC {
+			return
+		}
+		force, torque := body.Force(), body.Torque()
+		if saved, ok := d.previous[body]; ok {
+			force, torque = saved.force, saved.torque
+		}
+		// Force and torque are drawn as the accelerations they cause, which a body
+		// with no or infinite mass or moment does not have.
+		if mass := body.Mass(); m.Show&MotionForce != 0 && mass > 0 && !math.IsInf(mass, 0) {
+			d.drawArrow(cog, cog.Add(force.Mult(m.ForceScale/mass)), toFColor(d.Theme.Force))
+		}
+		if moment := body.Moment(); m.Show&MotionTorque != 0 && moment > 0 && !math.IsInf(moment, 0) {
+			d.drawArcArrow(cog, d.pixels(m.MarkerSize*3), angle, torque/moment*m.TorqueScale, toFColor(d.Theme.Torque))
+		}
+	})
+}
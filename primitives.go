@@ -0,0 +1,104 @@
+package ebitencp
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Arrow heads are at most this long in pixels.
+const arrowHeadLength = 6.0
+
+// arrowHead adds the two strokes of an arrow head at tip, pointing along dir.
+func arrowHead(path *worldPath, tip, dir cp.Vector, length float64) {
+	const spread = math.Pi / 7
+	back := dir.Normalize().Neg().Mult(length)
+	for _, a := range []float64{spread, -spread} {
+		path.MoveTo(tip)
+		path.LineTo(tip.Add(back.Rotate(cp.ForAngle(a))))
+	}
+}
+
+// drawArrow draws an arrow from a to b. Nothing is drawn when they are the same.
+func (d *Drawer) drawArrow(a, b cp.Vector, clr cp.FColor) {
+	dir := b.Sub(a)
+	length := dir.Length()
+	if length == 0 {
+		return
+	}
+	path := d.newPath()
+	path.MoveTo(a)
+	path.LineTo(b)
+	arrowHead(path, b, dir, math.Min(d.pixels(arrowHeadLength), length*0.4))
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
+
+// drawArc draws an arc around center from the start angle through sweep radians.
+// The sweep is limited to a full turn.
+func (d *Drawer) drawArc(center cp.Vector, radius, start, sweep float64, clr cp.FColor) {
+	if sweep == 0 || radius <= 0 {
+		return
+	}
+	sweep = cp.Clamp(sweep, -2*math.Pi, 2*math.Pi)
+	dir := vector.Clockwise
+	if sweep < 0 {
+		dir = vector.CounterClockwise
+	}
+	path := d.newPath()
+	path.Arc(center, radius, start, start+sweep, dir)
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
+
+// drawRing draws the outline of a circle.
+func (d *Drawer) drawRing(center cp.Vector, radius float64, clr cp.FColor) {
+	path := d.newPath()
+	path.Arc(center, radius, 0, 2*math.Pi, vector.Clockwise)
+	path.Close()
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
+
+// drawArcArrow draws an arc around center from the start angle through sweep radians,
+// with an arrow head at the end. The sweep is limited to almost a full turn.
+func (d *Drawer) drawArcArrow(center cp.Vector, radius, start, sweep float64, clr cp.FColor) {
+	if sweep == 0 || radius <= 0 {
+		return
+	}
+	const limit = 2*math.Pi - 0.1
+	sweep = cp.Clamp(sweep, -limit, limit)
+	end := start + sweep
+	dir := vector.Clockwise
+	if sweep < 0 {
+		dir = vector.CounterClockwise
+	}
+	path := d.newPath()
+	path.Arc(center, radius, start, end, dir)
+	tip := center.Add(cp.ForAngle(end).Mult(radius))
+	tangent := cp.ForAngle(end).Perp()
+	if sweep < 0 {
+		tangent = tangent.Neg()
+	}
+	arrowHead(path, tip, tangent, math.Min(d.pixels(arrowHeadLength), math.Abs(sweep)*radius*0.4))
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
+
+// drawCross draws a circle of radius with a cross through it, like a center of gravity marker.
+func (d *Drawer) drawCross(center cp.Vector, radius float64, clr cp.FColor) {
+	d.drawRing(center, radius, clr)
+	path := d.newPath()
+	path.MoveTo(center.Add(cp.Vector{X: -radius}))
+	path.LineTo(center.Add(cp.Vector{X: radius}))
+	path.MoveTo(center.Add(cp.Vector{Y: -radius}))
+	path.LineTo(center.Add(cp.Vector{Y: radius}))
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
+
+func (d *Drawer) drawBB(bb cp.BB, clr cp.FColor) {
+	path := d.newPath()
+	path.MoveTo(cp.Vector{X: bb.L, Y: bb.B})
+	path.LineTo(cp.Vector{X: bb.R, Y: bb.B})
+	path.LineTo(cp.Vector{X: bb.R, Y: bb.T})
+	path.LineTo(cp.Vector{X: bb.L, Y: bb.T})
+	path.Close()
+	d.drawOutline(d.Screen, path, clr.R, clr.G, clr.B, clr.A)
+}
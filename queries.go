@@ -0,0 +1,144 @@
+package ebitencp
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// The query methods below call the space query of the same name and queue
+// debug shapes that show the query and its hits on the next DrawSpace.
+// The query is drawn in Theme.Query and the hits in Theme.QueryHit.
+// Theme colors are not premultiplied, hence the conversions to color.NRGBA.
+
+// SegmentQuery is space.SegmentQuery. Every hit is drawn with its normal and alpha.
+func (d *Drawer) SegmentQuery(space *cp.Space, start, end cp.Vector, radius float64, filter cp.ShapeFilter, f cp.SpaceSegmentQueryFunc, data interface{}) {
+	d.queueSegment(start, end, radius, color.NRGBA(d.Theme.Query))
+	space.SegmentQuery(start, end, radius, filter, func(shape *cp.Shape, point, normal cp.Vector, alpha float64, data interface{}) {
+		d.queueSegmentHit(point, normal, alpha)
+		if f != nil {
+			f(shape, point, normal, alpha, data)
+		}
+	}, data)
+}
+
+// SegmentQueryFirst is space.SegmentQueryFirst.
+// The segment is drawn up to the hit, which is drawn with its normal and alpha.
+func (d *Drawer) SegmentQueryFirst(space *cp.Space, start, end cp.Vector, radius float64, filter cp.ShapeFilter) cp.SegmentQueryInfo {
+	info := space.SegmentQueryFirst(start, end, radius, filter)
+	if info.Shape == nil {
+		d.queueSegment(start, end, radius, color.NRGBA(d.Theme.Query))
+		return info
+	}
+	hit := start.Lerp(end, info.Alpha)
+	d.queueSegment(start, hit, radius, color.NRGBA(d.Theme.Query))
+	d.Line(hit, end, fade(color.NRGBA(d.Theme.Query)))
+	d.queueSegmentHit(info.Point, info.Normal, info.Alpha)
+	return info
+}
+
+// PointQuery calls f for every shape within maxDistance of point, like the
+// point query of Chipmunk that cp lacks. It is built on space.BBQuery and
+// Shape.PointQuery. Lines are drawn to the nearest point of every shape found.
+func (d *Drawer) PointQuery(space *cp.Space, point cp.Vector, maxDistance float64, filter cp.ShapeFilter, f cp.SpacePointQueryFunc, data interface{}) {
+	d.queuePointQuery(point, maxDistance)
+	space.BBQuery(cp.NewBBForCircle(point, maxDistance), filter, func(shape *cp.Shape, data interface{}) {
+		info := shape.PointQuery(point)
+		if info.Distance > maxDistance {
+			return
+		}
+		d.Line(point, info.Point, color.NRGBA(d.Theme.QueryHit))
+		d.Point(info.Point, color.NRGBA(d.Theme.QueryHit))
+		if f != nil {
+			f(shape, info.Point, info.Distance, info.Gradient, data)
+		}
+	}, data)
+}
+
+// PointQueryNearest is space.PointQueryNearest.
+// A line is drawn to the nearest point with the distance next to it.
+func (d *Drawer) PointQueryNearest(space *cp.Space, point cp.Vector, maxDistance float64, filter cp.ShapeFilter) *cp.PointQueryInfo {
+	d.queuePointQuery(point, maxDistance)
+	info := space.PointQueryNearest(point, maxDistance, filter)
+	if info.Shape != nil {
+		d.Line(point, info.Point, color.NRGBA(d.Theme.QueryHit))
+		d.Point(info.Point, color.NRGBA(d.Theme.QueryHit))
+		d.Text(point.Lerp(info.Point, 0.5), fmt.Sprintf("%.1f", info.Distance), color.NRGBA(d.Theme.QueryHit))
+	}
+	return info
+}
+
+// BBQuery is space.BBQuery. The box of every shape found is drawn.
+func (d *Drawer) BBQuery(space *cp.Space, bb cp.BB, filter cp.ShapeFilter, f cp.SpaceBBQueryFunc, data interface{}) {
+	d.Rect(bb, color.NRGBA(d.Theme.Query))
+	space.BBQuery(bb, filter, func(shape *cp.Shape, data interface{}) {
+		d.Rect(shape.BB(), color.NRGBA(d.Theme.QueryHit))
+		if f != nil {
+			f(shape, data)
+		}
+	}, data)
+}
+
+// ShapeQuery is space.ShapeQuery. The contact points of every shape found are drawn.
+func (d *Drawer) ShapeQuery(space *cp.Space, shape *cp.Shape, callback func(shape *cp.Shape, points *cp.ContactPointSet)) bool {
+	hit := space.ShapeQuery(shape, func(other *cp.Shape, points *cp.ContactPointSet) {
+		d.Rect(other.BB(), fade(color.NRGBA(d.Theme.QueryHit)))
+		for i := 0; i < points.Count; i++ {
+			d.Line(points.Points[i].PointA, points.Points[i].PointB, color.NRGBA(d.Theme.QueryHit))
+			d.Point(points.Points[i].PointA, color.NRGBA(d.Theme.QueryHit))
+		}
+		if callback != nil {
+			callback(other, points)
+		}
+	})
+	d.queueShape(shape, color.NRGBA(d.Theme.Query))
+	return hit
+}
+
+func (d *Drawer) queueSegment(a, b cp.Vector, radius float64, clr color.NRGBA) {
+	d.Line(a, b, clr)
+	if radius > 0 {
+		side := b.Sub(a).Normalize().Perp().Mult(radius)
+		d.Polygon([]cp.Vector{a.Add(side), b.Add(side), b.Sub(side), a.Sub(side)}, fade(clr))
+	}
+}
+
+func (d *Drawer) queueSegmentHit(point, normal cp.Vector, alpha float64) {
+	d.Point(point, color.NRGBA(d.Theme.QueryHit))
+	d.Arrow(point, point.Add(normal.Mult(d.pixels(arrowHeadLength*2))), color.NRGBA(d.Theme.QueryHit))
+	d.Text(point, fmt.Sprintf("%.2f", alpha), color.NRGBA(d.Theme.QueryHit))
+}
+
+func (d *Drawer) queuePointQuery(point cp.Vector, maxDistance float64) {
+	d.Point(point, color.NRGBA(d.Theme.Query))
+	if maxDistance > 0 {
+		d.Circle(point, maxDistance, color.NRGBA(d.Theme.Query))
+	}
+}
+
+// queueShape queues the outline of shape as it is placed now.
+func (d *Drawer) queueShape(shape *cp.Shape, clr color.NRGBA) {
+	body := shape.Body()
+	if body == nil {
+		d.Rect(shape.BB(), clr)
+		return
+	}
+	switch class := shape.Class.(type) {
+	case *cp.Circle:
+		d.Circle(body.LocalToWorld(circleOffset(class)), class.Radius(), clr)
+	case *cp.Segment:
+		d.queueSegment(body.LocalToWorld(class.A()), body.LocalToWorld(class.B()), class.Radius(), clr)
+	case *cp.PolyShape:
+		verts := make([]cp.Vector, class.Count())
+		for i := range verts {
+			verts[i] = body.LocalToWorld(class.Vert(i))
+		}
+		d.Polygon(verts, clr)
+	}
+}
+
+// fade halves the alpha of clr.
+func fade(clr color.NRGBA) color.NRGBA {
+	return color.NRGBA{clr.R, clr.G, clr.B, clr.A / 2}
+}
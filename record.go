@@ -0,0 +1,184 @@
+package ebitencp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// RecordingVersion is the format version written by Recording.Save.
+const RecordingVersion = 1
+
+// Recording is the initial state of a space followed by
+// the input and the time steps of every frame.
+type Recording struct {
+	Version  int             `json:"version"`
+	Snapshot *Snapshot       `json:"snapshot"`
+	Frames   []RecordedFrame `json:"frames"`
+}
+
+// RecordedFrame is the input consumed by HandleMouseEvent in one frame
+// and the dt of every space.Step that followed it.
+type RecordedFrame struct {
+	Input Input     `json:"input"`
+	Steps []float64 `json:"steps"`
+}
+
+// Save writes the recording as JSON.
+func (r *Recording) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// LoadRecording reads a recording written by Recording.Save.
+func LoadRecording(r io.Reader) (*Recording, error) {
+	rec := &Recording{}
+	if err := json.NewDecoder(r).Decode(rec); err != nil {
+		return nil, err
+	}
+	if rec.Version != RecordingVersion {
+		return nil, fmt.Errorf("ebitencp: unsupported recording version %d", rec.Version)
+	}
+	if rec.Snapshot == nil {
+		return nil, fmt.Errorf("ebitencp: recording has no snapshot")
+	}
+	return rec, nil
+}
+
+// Recorder records a session for Replayer.
+//
+// Cached contacts and solver state are not part of a snapshot, so the
+// recorder simulates a space restored from its snapshot, just as the
+// replayer does. Simulate Recorder.Space() instead of the original space.
+//
+//	func (g *Game) Update() error {
+//		g.recorder.HandleMouseEvent()
+//		g.recorder.Step(1 / 60.0)
+//		return nil
+//	}
+type Recorder struct {
+	drawer    *Drawer
+	space     *cp.Space
+	recording Recording
+}
+
+// NewRecorder starts recording the current state of space.
+// Any body dragged by d is released first.
+func NewRecorder(d *Drawer, space *cp.Space) (*Recorder, error) {
+	d.handler.reset()
+	snapshot, err := TakeSnapshot(space)
+	if err != nil {
+		return nil, err
+	}
+	restored, err := snapshot.Restore()
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		drawer: d,
+		space:  restored,
+		recording: Recording{
+			Version:  RecordingVersion,
+			Snapshot: snapshot,
+		},
+	}, nil
+}
+
+// Space returns the space being recorded.
+func (r *Recorder) Space() *cp.Space {
+	return r.space
+}
+
+// HandleMouseEvent reads and records the input of a new frame and drags bodies with it.
+func (r *Recorder) HandleMouseEvent() {
+	r.handleInput(r.drawer.ReadInput())
+}
+
+// handleInput records in as the input of a new frame and drags bodies with it.
+func (r *Recorder) handleInput(in Input) {
+	r.recording.Frames = append(r.recording.Frames, RecordedFrame{Input: in})
+	r.drawer.HandleInput(r.space, in)
+}
+
+// Step steps the space and records dt in the current frame.
+func (r *Recorder) Step(dt float64) {
+	if len(r.recording.Frames) == 0 {
+		r.recording.Frames = append(r.recording.Frames, RecordedFrame{})
+	}
+	frame := &r.recording.Frames[len(r.recording.Frames)-1]
+	frame.Steps = append(frame.Steps, dt)
+	r.space.Step(dt)
+}
+
+// Recording returns what has been recorded so far.
+func (r *Recorder) Recording() *Recording {
+	return &r.recording
+}
+
+// Replayer plays a Recording back frame by frame.
+type Replayer struct {
+	drawer    *Drawer
+	recording *Recording
+	space     *cp.Space
+	frame     int
+}
+
+// NewReplayer restores the initial state of rec.
+// Dragging is replayed through d, which should not be dragging anything else.
+func NewReplayer(d *Drawer, rec *Recording) (*Replayer, error) {
+	p := &Replayer{
+		drawer:    d,
+		recording: rec,
+	}
+	if err := p.Rewind(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Space returns the space being replayed. It changes on Rewind.
+func (p *Replayer) Space() *cp.Space {
+	return p.space
+}
+
+// Frame returns the number of frames played so far.
+func (p *Replayer) Frame() int {
+	return p.frame
+}
+
+// Len returns the number of recorded frames.
+func (p *Replayer) Len() int {
+	return len(p.recording.Frames)
+}
+
+// Done reports whether every frame has been played.
+func (p *Replayer) Done() bool {
+	return p.frame >= len(p.recording.Frames)
+}
+
+// Step plays the next frame. It returns false when there are no more frames.
+func (p *Replayer) Step() bool {
+	if p.Done() {
+		return false
+	}
+	frame := p.recording.Frames[p.frame]
+	p.drawer.HandleInput(p.space, frame.Input)
+	for _, dt := range frame.Steps {
+		p.space.Step(dt)
+	}
+	p.frame++
+	return true
+}
+
+// Rewind restores the initial state of the recording.
+func (p *Replayer) Rewind() error {
+	space, err := p.recording.Snapshot.Restore()
+	if err != nil {
+		return err
+	}
+	p.drawer.handler.reset()
+	p.space = space
+	p.frame = 0
+	return nil
+}
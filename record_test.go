@@ -0,0 +1,76 @@
+package ebitencp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+func TestRecordReplay(t *testing.T) {
+	space := cp.NewSpace()
+	space.SetGravity(cp.Vector{X: 0, Y: -100})
+	space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: -50, Y: -10}, cp.Vector{X: 50, Y: -10}, 1))
+	addTestBox(space, cp.Vector{X: 0, Y: 0})
+	addTestBox(space, cp.Vector{X: 0.5, Y: 3})
+
+	recorder, err := NewRecorder(&Drawer{}, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Grab the lower box, drag it to the side and let it go.
+	inputs := make([]Input, 60)
+	for i := range inputs {
+		inputs[i].Cursor = cp.Vector{X: float64(i) * 0.2, Y: 0}
+	}
+	inputs[0].Pressed = true
+	inputs[40].Released = true
+
+	var recorded [][]cp.Vector
+	for _, in := range inputs {
+		recorder.handleInput(in)
+		recorder.Step(1 / 60.0)
+		recorder.Step(1 / 60.0)
+		recorded = append(recorded, bodyPositions(recorder.Space()))
+	}
+
+	var buf bytes.Buffer
+	if err := recorder.Recording().Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := LoadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer, err := NewReplayer(&Drawer{}, rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayer.Len() != len(inputs) {
+		t.Fatalf("Len() = %d, want %d", replayer.Len(), len(inputs))
+	}
+	for frame := 0; replayer.Step(); frame++ {
+		got := bodyPositions(replayer.Space())
+		for i := range got {
+			if got[i] != recorded[frame][i] {
+				t.Fatalf("frame %d: body %d at %v, recorded at %v", frame, i, got[i], recorded[frame][i])
+			}
+		}
+	}
+	if !replayer.Done() {
+		t.Error("Done() = false after the last frame")
+	}
+	if x := recorded[len(recorded)-1][0].X; x < 1 {
+		t.Errorf("the dragged box ended at x = %g, want it dragged to the right", x)
+	}
+}
+
+func bodyPositions(space *cp.Space) []cp.Vector {
+	var positions []cp.Vector
+	space.EachBody(func(body *cp.Body) {
+		if body.GetType() == cp.BODY_DYNAMIC {
+			positions = append(positions, body.Position())
+		}
+	})
+	return positions
+}
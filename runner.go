@@ -0,0 +1,193 @@
+package ebitencp
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// RunnerOptions configures a Runner. Zero values are replaced by defaults.
+type RunnerOptions struct {
+	// Screen size. The default is 640x480.
+	ScreenWidth, ScreenHeight int
+	// Title is the window title.
+	Title string
+	// TimeStep is the fixed dt passed to space.Step. The default is 1/60.
+	TimeStep float64
+	// MaxSubsteps limits the steps taken in one Update. The default is 5.
+	MaxSubsteps int
+	FlipYAxis   bool
+	// GeoM is the initial camera of the drawer.
+	GeoM ebiten.GeoM
+	// Theme is the drawing colors. The default is DefaultTheme().
+	Theme *Theme
+	// DebugHUD shows a HUD with physics statistics.
+	DebugHUD bool
+}
+
+// Runner is an ebiten.Game that steps a space on a fixed timestep,
+// lets bodies be dragged and draws them.
+//
+//	runner := ebitencp.NewRunner(space, nil)
+//	if err := runner.Run(); err != nil {
+//		log.Fatal(err)
+//	}
+type Runner struct {
+	Space       *cp.Space
+	Drawer      *Drawer
+	TimeStep    float64
+	MaxSubsteps int
+	// HUD is drawn over the space when it is not nil.
+	HUD *HUD
+	// Control pauses, steps and slows down the space when it is not nil.
+	// Its state is shown in the HUD.
+	Control *SimulationControl
+
+	// PreStep and PostStep are called around every space.Step.
+	PreStep  func(space *cp.Space, dt float64)
+	PostStep func(space *cp.Space, dt float64)
+	// Overlay is called after the space is drawn.
+	Overlay func(screen *ebiten.Image)
+
+	screenWidth  int
+	screenHeight int
+	title        string
+	accumulator  float64
+	lastUpdate   time.Time
+}
+
+func NewRunner(space *cp.Space, opts *RunnerOptions) *Runner {
+	if opts == nil {
+		opts = &RunnerOptions{}
+	}
+	r := &Runner{
+		Space:        space,
+		TimeStep:     opts.TimeStep,
+		MaxSubsteps:  opts.MaxSubsteps,
+		screenWidth:  opts.ScreenWidth,
+		screenHeight: opts.ScreenHeight,
+		title:        opts.Title,
+	}
+	if r.TimeStep <= 0 {
+		r.TimeStep = 1 / 60.0
+	}
+	if r.MaxSubsteps <= 0 {
+		r.MaxSubsteps = 5
+	}
+	if r.screenWidth <= 0 || r.screenHeight <= 0 {
+		r.screenWidth, r.screenHeight = 640, 480
+	}
+	r.Drawer = NewDrawer(r.screenWidth, r.screenHeight)
+	r.Drawer.FlipYAxis = opts.FlipYAxis
+	*r.Drawer.GeoM = opts.GeoM
+	if opts.Theme != nil {
+		r.Drawer.Theme = opts.Theme
+	}
+	if opts.DebugHUD {
+		r.HUD = NewHUD()
+	}
+	return r
+}
+
+// Run opens the window and runs the game.
+func (r *Runner) Run() error {
+	ebiten.SetWindowSize(r.screenWidth, r.screenHeight)
+	if r.title != "" {
+		ebiten.SetWindowTitle(r.title)
+	}
+	return ebiten.RunGame(r)
+}
+
+func (r *Runner) Update() error {
+	frameTime := r.frameTime()
+	steps := 0
+	if r.Control != nil {
+		if err := r.Control.HandleKeys(); err != nil {
+			return err
+		}
+		if err := r.Control.HandleMouseEvent(); err != nil {
+			return err
+		}
+		steps = r.Control.steps(frameTime, r.TimeStep)
+	} else {
+		r.Drawer.HandleMouseEvent(r.Space)
+		r.accumulator += frameTime
+		for r.accumulator >= r.TimeStep {
+			r.accumulator -= r.TimeStep
+			steps++
+		}
+	}
+	// Too far behind. Drop the time rather than trying to catch up.
+	steps = min(steps, r.MaxSubsteps)
+	for i := 0; i < steps; i++ {
+		if err := r.step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// frameTime returns the time that one Update stands for.
+func (r *Runner) frameTime() float64 {
+	if tps := ebiten.TPS(); tps != ebiten.SyncWithFPS {
+		return 1 / float64(tps)
+	}
+	now := time.Now()
+	var dt float64
+	if !r.lastUpdate.IsZero() {
+		dt = now.Sub(r.lastUpdate).Seconds()
+	}
+	r.lastUpdate = now
+	return dt
+}
+
+func (r *Runner) step() error {
+	dt := r.TimeStep
+	if r.PreStep != nil {
+		r.PreStep(r.Space, dt)
+	}
+	r.Drawer.SaveTransforms(r.Space)
+	if r.HUD != nil {
+		r.HUD.Step(r.Space, dt)
+	} else {
+		r.Space.Step(dt)
+	}
+	if r.PostStep != nil {
+		r.PostStep(r.Space, dt)
+	}
+	if r.Control != nil {
+		r.Control.record()
+	}
+	return nil
+}
+
+func (r *Runner) Draw(screen *ebiten.Image) {
+	r.Drawer.Alpha = r.alpha()
+	r.Drawer.WithScreen(screen).DrawSpace(r.Space)
+	if r.Overlay != nil {
+		r.Overlay(screen)
+	}
+	if r.Control != nil && r.Control.History != nil {
+		r.Control.History.DrawTimeline(screen)
+	}
+	if r.HUD != nil {
+		r.HUD.Control = r.Control
+		r.HUD.Draw(screen, r.Space, r.Drawer)
+	}
+}
+
+func (r *Runner) alpha() float64 {
+	if r.Control == nil {
+		return r.accumulator / r.TimeStep
+	}
+	if r.Control.Paused {
+		// Single steps and dragging must show up at once.
+		return 1
+	}
+	return r.Control.accumulator / r.TimeStep
+}
+
+func (r *Runner) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return r.screenWidth, r.screenHeight
+}
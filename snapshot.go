@@ -0,0 +1,549 @@
+package ebitencp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// SnapshotVersion is the format version written by TakeSnapshot.
+const SnapshotVersion = 1
+
+// Snapshot is a serializable copy of a cp.Space.
+//
+// The JSON format is:
+//
+//	{
+//	  "version": 1,
+//	  "space": {"gravity": {"X": 0, "Y": -100}, "damping": 1, ...},
+//	  "bodies": [{"type": "static", ...}, {"type": "dynamic", "mass": 1, ...}],
+//	  "shapes": [{"type": "circle", "body": 1, "radius": 10, ...}],
+//	  "constraints": [{"type": "pivot", "body_a": 0, "body_b": 1, ...}]
+//	}
+//
+// Shapes and constraints refer to bodies by their index in "bodies".
+// Index 0 is always the space's static body.
+// Vectors are objects with "X" and "Y" members.
+//
+// Callbacks, UserData, custom spring force functions, sleeping state and
+// the solver's cached impulses are not stored. Sleeping bodies are restored awake.
+// Constraints attached to a body outside the space, such as the joint
+// HandleMouseEvent uses for dragging, are an error. Release them first.
+type Snapshot struct {
+	Version     int                  `json:"version"`
+	Space       SpaceSnapshot        `json:"space"`
+	Bodies      []BodySnapshot       `json:"bodies"`
+	Shapes      []ShapeSnapshot      `json:"shapes"`
+	Constraints []ConstraintSnapshot `json:"constraints"`
+}
+
+type SpaceSnapshot struct {
+	Gravity            cp.Vector `json:"gravity"`
+	Damping            float64   `json:"damping"`
+	Iterations         uint      `json:"iterations"`
+	IdleSpeedThreshold float64   `json:"idle_speed_threshold"`
+	SleepTimeThreshold float64   `json:"sleep_time_threshold"`
+	CollisionSlop      float64   `json:"collision_slop"`
+}
+
+// Body types used in BodySnapshot.Type.
+const (
+	BodyTypeDynamic   = "dynamic"
+	BodyTypeKinematic = "kinematic"
+	BodyTypeStatic    = "static"
+)
+
+type BodySnapshot struct {
+	Type            string    `json:"type"`
+	Mass            float64   `json:"mass"`
+	Moment          float64   `json:"moment"`
+	Position        cp.Vector `json:"position"`
+	Angle           float64   `json:"angle"`
+	Velocity        cp.Vector `json:"velocity"`
+	AngularVelocity float64   `json:"angular_velocity"`
+	Force           cp.Vector `json:"force"`
+	Torque          float64   `json:"torque"`
+}
+
+// Shape types used in ShapeSnapshot.Type.
+const (
+	ShapeTypeCircle  = "circle"
+	ShapeTypeSegment = "segment"
+	ShapeTypePoly    = "poly"
+)
+
+// ShapeSnapshot holds a shape. Offset is used by circles, A and B by
+// segments and Verts by polygons. All of them are in body-local coordinates.
+type ShapeSnapshot struct {
+	Type   string      `json:"type"`
+	Body   int         `json:"body"`
+	Radius float64     `json:"radius"`
+	Offset *cp.Vector  `json:"offset,omitempty"`
+	A      *cp.Vector  `json:"a,omitempty"`
+	B      *cp.Vector  `json:"b,omitempty"`
+	Verts  []cp.Vector `json:"verts,omitempty"`
+
+	Mass            float64          `json:"mass"`
+	Friction        float64          `json:"friction"`
+	Elasticity      float64          `json:"elasticity"`
+	SurfaceVelocity cp.Vector        `json:"surface_velocity"`
+	Filter          cp.ShapeFilter   `json:"filter"`
+	CollisionType   cp.CollisionType `json:"collision_type"`
+	Sensor          bool             `json:"sensor"`
+}
+
+// Constraint types used in ConstraintSnapshot.Type.
+const (
+	ConstraintTypePivot              = "pivot"
+	ConstraintTypePin                = "pin"
+	ConstraintTypeSlide              = "slide"
+	ConstraintTypeGroove             = "groove"
+	ConstraintTypeDampedSpring       = "damped_spring"
+	ConstraintTypeDampedRotarySpring = "damped_rotary_spring"
+	ConstraintTypeRotaryLimit        = "rotary_limit"
+	ConstraintTypeRatchet            = "ratchet"
+	ConstraintTypeGear               = "gear"
+	ConstraintTypeSimpleMotor        = "simple_motor"
+)
+
+// ConstraintSnapshot holds a constraint.
+// Only the fields used by Type are set; the others are omitted from JSON.
+type ConstraintSnapshot struct {
+	Type          string  `json:"type"`
+	BodyA         int     `json:"body_a"`
+	BodyB         int     `json:"body_b"`
+	MaxForce      float64 `json:"max_force"`
+	MaxBias       float64 `json:"max_bias"`
+	ErrorBias     float64 `json:"error_bias"`
+	CollideBodies bool    `json:"collide_bodies"`
+
+	AnchorA *cp.Vector `json:"anchor_a,omitempty"` // pivot, pin, slide, damped_spring
+	AnchorB *cp.Vector `json:"anchor_b,omitempty"` // pivot, pin, slide, groove, damped_spring
+	GrooveA *cp.Vector `json:"groove_a,omitempty"` // groove
+	GrooveB *cp.Vector `json:"groove_b,omitempty"` // groove
+
+	Dist       float64 `json:"dist,omitempty"`        // pin
+	Min        float64 `json:"min,omitempty"`         // slide, rotary_limit
+	Max        float64 `json:"max,omitempty"`         // slide, rotary_limit
+	RestLength float64 `json:"rest_length,omitempty"` // damped_spring
+	RestAngle  float64 `json:"rest_angle,omitempty"`  // damped_rotary_spring
+	Stiffness  float64 `json:"stiffness,omitempty"`   // damped_spring, damped_rotary_spring
+	Damping    float64 `json:"damping,omitempty"`     // damped_spring, damped_rotary_spring
+	Phase      float64 `json:"phase,omitempty"`       // ratchet, gear
+	Ratchet    float64 `json:"ratchet,omitempty"`     // ratchet
+	Angle      float64 `json:"angle,omitempty"`       // ratchet
+	Ratio      float64 `json:"ratio,omitempty"`       // gear
+	Rate       float64 `json:"rate,omitempty"`        // simple_motor
+}
+
+// MarshalSpace encodes the state of space as JSON.
+func MarshalSpace(space *cp.Space) ([]byte, error) {
+	s, err := TakeSnapshot(space)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalSpace creates a new space from JSON written by MarshalSpace.
+func UnmarshalSpace(data []byte) (*cp.Space, error) {
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s.Restore()
+}
+
+// TakeSnapshot copies the state of space.
+func TakeSnapshot(space *cp.Space) (*Snapshot, error) {
+	s := &Snapshot{
+		Version: SnapshotVersion,
+		Space: SpaceSnapshot{
+			Gravity:            space.Gravity(),
+			Damping:            space.Damping(),
+			Iterations:         space.Iterations,
+			IdleSpeedThreshold: space.IdleSpeedThreshold,
+			SleepTimeThreshold: space.SleepTimeThreshold,
+			CollisionSlop:      spaceCollisionSlop(space),
+		},
+	}
+
+	bodies := []*cp.Body{space.StaticBody}
+	space.EachBody(func(body *cp.Body) {
+		if body != space.StaticBody {
+			bodies = append(bodies, body)
+		}
+	})
+	index := make(map[*cp.Body]int, len(bodies))
+	for i, body := range bodies {
+		index[body] = i
+		s.Bodies = append(s.Bodies, snapshotBody(body))
+	}
+
+	var err error
+	for i, body := range bodies {
+		body.EachShape(func(shape *cp.Shape) {
+			if err != nil {
+				return
+			}
+			var ss ShapeSnapshot
+			ss, err = snapshotShape(shape)
+			ss.Body = i
+			s.Shapes = append(s.Shapes, ss)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	space.EachConstraint(func(c *cp.Constraint) {
+		if err != nil {
+			return
+		}
+		a, b := constraintBodies(c)
+		ia, okA := index[a]
+		ib, okB := index[b]
+		if !okA || !okB {
+			err = fmt.Errorf("ebitencp: constraint %T is attached to a body that is not in the space", c.Class)
+			return
+		}
+		var cs ConstraintSnapshot
+		cs, err = snapshotConstraint(c)
+		cs.BodyA, cs.BodyB = ia, ib
+		s.Constraints = append(s.Constraints, cs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func snapshotBody(body *cp.Body) BodySnapshot {
+	bs := BodySnapshot{
+		Mass:            body.Mass(),
+		Moment:          body.Moment(),
+		Position:        body.Position(),
+		Angle:           body.Angle(),
+		Velocity:        body.Velocity(),
+		AngularVelocity: body.AngularVelocity(),
+		Force:           body.Force(),
+		Torque:          body.Torque(),
+	}
+	switch body.GetType() {
+	case cp.BODY_DYNAMIC:
+		bs.Type = BodyTypeDynamic
+	case cp.BODY_KINEMATIC:
+		bs.Type = BodyTypeKinematic
+	case cp.BODY_STATIC:
+		bs.Type = BodyTypeStatic
+	}
+	return bs
+}
+
+func snapshotShape(shape *cp.Shape) (ShapeSnapshot, error) {
+	ss := ShapeSnapshot{
+		Mass:            shape.Mass(),
+		Friction:        shape.Friction(),
+		Elasticity:      shape.Elasticity(),
+		SurfaceVelocity: shapeSurfaceV(shape),
+		Filter:          shape.Filter,
+		CollisionType:   shapeCollisionType(shape),
+		Sensor:          shape.Sensor(),
+	}
+	switch class := shape.Class.(type) {
+	case *cp.Circle:
+		ss.Type = ShapeTypeCircle
+		ss.Radius = class.Radius()
+		ss.Offset = vectorPtr(circleOffset(class))
+	case *cp.Segment:
+		ss.Type = ShapeTypeSegment
+		ss.Radius = class.Radius()
+		ss.A, ss.B = vectorPtr(class.A()), vectorPtr(class.B())
+	case *cp.PolyShape:
+		ss.Type = ShapeTypePoly
+		ss.Radius = class.Radius()
+		ss.Verts = make([]cp.Vector, class.Count())
+		for i := range ss.Verts {
+			ss.Verts[i] = class.Vert(i)
+		}
+	default:
+		return ss, fmt.Errorf("ebitencp: unknown shape type %T", shape.Class)
+	}
+	return ss, nil
+}
+
+func snapshotConstraint(c *cp.Constraint) (ConstraintSnapshot, error) {
+	cs := ConstraintSnapshot{
+		MaxForce:      c.MaxForce(),
+		MaxBias:       c.MaxBias(),
+		ErrorBias:     c.ErrorBias(),
+		CollideBodies: constraintCollideBodies(c),
+	}
+	switch joint := c.Class.(type) {
+	case *cp.PivotJoint:
+		cs.Type = ConstraintTypePivot
+		cs.AnchorA, cs.AnchorB = vectorPtr(joint.AnchorA), vectorPtr(joint.AnchorB)
+	case *cp.PinJoint:
+		cs.Type = ConstraintTypePin
+		cs.AnchorA, cs.AnchorB = vectorPtr(joint.AnchorA), vectorPtr(joint.AnchorB)
+		cs.Dist = joint.Dist
+	case *cp.SlideJoint:
+		cs.Type = ConstraintTypeSlide
+		cs.AnchorA, cs.AnchorB = vectorPtr(joint.AnchorA), vectorPtr(joint.AnchorB)
+		cs.Min, cs.Max = joint.Min, joint.Max
+	case *cp.GrooveJoint:
+		cs.Type = ConstraintTypeGroove
+		cs.GrooveA, cs.GrooveB = vectorPtr(joint.GrooveA), vectorPtr(joint.GrooveB)
+		cs.AnchorB = vectorPtr(joint.AnchorB)
+	case *cp.DampedSpring:
+		cs.Type = ConstraintTypeDampedSpring
+		cs.AnchorA, cs.AnchorB = vectorPtr(joint.AnchorA), vectorPtr(joint.AnchorB)
+		cs.RestLength, cs.Stiffness, cs.Damping = joint.RestLength, joint.Stiffness, joint.Damping
+	case *cp.DampedRotarySpring:
+		cs.Type = ConstraintTypeDampedRotarySpring
+		cs.RestAngle, cs.Stiffness, cs.Damping = joint.RestAngle, joint.Stiffness, joint.Damping
+	case *cp.RotaryLimitJoint:
+		cs.Type = ConstraintTypeRotaryLimit
+		cs.Min, cs.Max = joint.Min, joint.Max
+	case *cp.RatchetJoint:
+		cs.Type = ConstraintTypeRatchet
+		cs.Phase, cs.Ratchet, cs.Angle = joint.Phase, joint.Ratchet, joint.Angle
+	case *cp.GearJoint:
+		cs.Type = ConstraintTypeGear
+		cs.Phase, cs.Ratio = gearJointParams(joint)
+	case *cp.SimpleMotor:
+		cs.Type = ConstraintTypeSimpleMotor
+		cs.Rate = joint.Rate
+	default:
+		return cs, fmt.Errorf("ebitencp: unknown constraint type %T", c.Class)
+	}
+	return cs, nil
+}
+
+// Restore creates a new space from the snapshot.
+func (s *Snapshot) Restore() (*cp.Space, error) {
+	space := cp.NewSpace()
+	if err := s.RestoreInto(space); err != nil {
+		return nil, err
+	}
+	return space, nil
+}
+
+// RestoreInto replaces the bodies, shapes and constraints of space with
+// the ones in the snapshot. Pointers to the old bodies become stale.
+func (s *Snapshot) RestoreInto(space *cp.Space) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	clearSpace(space)
+
+	space.SetGravity(s.Space.Gravity)
+	space.SetDamping(s.Space.Damping)
+	space.Iterations = s.Space.Iterations
+	space.IdleSpeedThreshold = s.Space.IdleSpeedThreshold
+	space.SleepTimeThreshold = s.Space.SleepTimeThreshold
+	space.SetCollisionSlop(s.Space.CollisionSlop)
+
+	bodies := make([]*cp.Body, len(s.Bodies))
+	bodies[0] = space.StaticBody
+	for i := 1; i < len(s.Bodies); i++ {
+		bs := s.Bodies[i]
+		switch bs.Type {
+		case BodyTypeDynamic:
+			bodies[i] = cp.NewBody(bs.Mass, bs.Moment)
+		case BodyTypeKinematic:
+			bodies[i] = cp.NewKinematicBody()
+		case BodyTypeStatic:
+			bodies[i] = cp.NewStaticBody()
+		default:
+			return fmt.Errorf("ebitencp: unknown body type %q", bs.Type)
+		}
+		space.AddBody(bodies[i])
+	}
+	for i, bs := range s.Bodies {
+		body := bodies[i]
+		body.SetAngle(bs.Angle)
+		body.SetPosition(bs.Position)
+		body.SetVelocityVector(bs.Velocity)
+		body.SetAngularVelocity(bs.AngularVelocity)
+		body.SetForce(bs.Force)
+		body.SetTorque(bs.Torque)
+	}
+
+	for _, ss := range s.Shapes {
+		shape, err := ss.newShape(bodies[ss.Body])
+		if err != nil {
+			return err
+		}
+		space.AddShape(shape)
+	}
+
+	// Adding a shape with mass recomputes the mass and center of gravity
+	// of a dynamic body, so they are set again afterwards.
+	for i, bs := range s.Bodies {
+		if bs.Type != BodyTypeDynamic {
+			continue
+		}
+		body := bodies[i]
+		body.SetMass(bs.Mass)
+		body.SetMoment(bs.Moment)
+		body.SetPosition(bs.Position)
+	}
+
+	for _, cs := range s.Constraints {
+		c, err := cs.newConstraint(bodies[cs.BodyA], bodies[cs.BodyB])
+		if err != nil {
+			return err
+		}
+		space.AddConstraint(c)
+	}
+	return nil
+}
+
+// validate checks the snapshot before a space is touched.
+// The types themselves are checked again when the objects are created.
+func (s *Snapshot) validate() error {
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("ebitencp: unsupported snapshot version %d", s.Version)
+	}
+	if len(s.Bodies) == 0 || s.Bodies[0].Type != BodyTypeStatic {
+		return fmt.Errorf("ebitencp: snapshot must start with the static body")
+	}
+	for i, bs := range s.Bodies {
+		switch bs.Type {
+		case BodyTypeDynamic:
+			// NaN fails both comparisons.
+			if !(bs.Mass > 0) || !(bs.Moment > 0) {
+				return fmt.Errorf("ebitencp: dynamic body %d has mass %g and moment %g", i, bs.Mass, bs.Moment)
+			}
+		case BodyTypeKinematic, BodyTypeStatic:
+		default:
+			return fmt.Errorf("ebitencp: unknown body type %q", bs.Type)
+		}
+	}
+	for _, ss := range s.Shapes {
+		if ss.Body < 0 || ss.Body >= len(s.Bodies) {
+			return fmt.Errorf("ebitencp: shape refers to missing body %d", ss.Body)
+		}
+		switch ss.Type {
+		case ShapeTypePoly:
+			if len(ss.Verts) < 3 {
+				return fmt.Errorf("ebitencp: poly shape has %d verts", len(ss.Verts))
+			}
+		case ShapeTypeCircle, ShapeTypeSegment:
+		default:
+			return fmt.Errorf("ebitencp: unknown shape type %q", ss.Type)
+		}
+	}
+	for _, cs := range s.Constraints {
+		if cs.BodyA < 0 || cs.BodyA >= len(s.Bodies) || cs.BodyB < 0 || cs.BodyB >= len(s.Bodies) {
+			return fmt.Errorf("ebitencp: constraint refers to missing body %d or %d", cs.BodyA, cs.BodyB)
+		}
+		switch cs.Type {
+		case ConstraintTypePivot, ConstraintTypePin, ConstraintTypeSlide, ConstraintTypeGroove,
+			ConstraintTypeDampedSpring, ConstraintTypeDampedRotarySpring, ConstraintTypeRotaryLimit,
+			ConstraintTypeRatchet, ConstraintTypeGear, ConstraintTypeSimpleMotor:
+		default:
+			return fmt.Errorf("ebitencp: unknown constraint type %q", cs.Type)
+		}
+	}
+	return nil
+}
+
+func (ss ShapeSnapshot) newShape(body *cp.Body) (*cp.Shape, error) {
+	var shape *cp.Shape
+	switch ss.Type {
+	case ShapeTypeCircle:
+		shape = cp.NewCircle(body, ss.Radius, vectorOrZero(ss.Offset))
+	case ShapeTypeSegment:
+		shape = cp.NewSegment(body, vectorOrZero(ss.A), vectorOrZero(ss.B), ss.Radius)
+	case ShapeTypePoly:
+		shape = cp.NewPolyShapeRaw(body, len(ss.Verts), ss.Verts, ss.Radius)
+	default:
+		return nil, fmt.Errorf("ebitencp: unknown shape type %q", ss.Type)
+	}
+	if ss.Mass > 0 {
+		shape.SetMass(ss.Mass)
+	}
+	shape.SetFriction(ss.Friction)
+	shape.SetElasticity(ss.Elasticity)
+	shape.SetSurfaceV(ss.SurfaceVelocity)
+	shape.SetFilter(ss.Filter)
+	shape.SetCollisionType(ss.CollisionType)
+	shape.SetSensor(ss.Sensor)
+	return shape, nil
+}
+
+func (cs ConstraintSnapshot) newConstraint(a, b *cp.Body) (*cp.Constraint, error) {
+	var c *cp.Constraint
+	switch cs.Type {
+	case ConstraintTypePivot:
+		c = cp.NewPivotJoint2(a, b, vectorOrZero(cs.AnchorA), vectorOrZero(cs.AnchorB))
+	case ConstraintTypePin:
+		c = cp.NewPinJoint(a, b, vectorOrZero(cs.AnchorA), vectorOrZero(cs.AnchorB))
+		c.Class.(*cp.PinJoint).Dist = cs.Dist
+	case ConstraintTypeSlide:
+		c = cp.NewSlideJoint(a, b, vectorOrZero(cs.AnchorA), vectorOrZero(cs.AnchorB), cs.Min, cs.Max)
+	case ConstraintTypeGroove:
+		c = cp.NewGrooveJoint(a, b, vectorOrZero(cs.GrooveA), vectorOrZero(cs.GrooveB), vectorOrZero(cs.AnchorB))
+	case ConstraintTypeDampedSpring:
+		c = cp.NewDampedSpring(a, b, vectorOrZero(cs.AnchorA), vectorOrZero(cs.AnchorB), cs.RestLength, cs.Stiffness, cs.Damping)
+	case ConstraintTypeDampedRotarySpring:
+		c = cp.NewDampedRotarySpring(a, b, cs.RestAngle, cs.Stiffness, cs.Damping)
+	case ConstraintTypeRotaryLimit:
+		c = cp.NewRotaryLimitJoint(a, b, cs.Min, cs.Max)
+	case ConstraintTypeRatchet:
+		c = cp.NewRatchetJoint(a, b, cs.Phase, cs.Ratchet)
+		c.Class.(*cp.RatchetJoint).Angle = cs.Angle
+	case ConstraintTypeGear:
+		c = cp.NewGearJoint(a, b, cs.Phase, cs.Ratio)
+	case ConstraintTypeSimpleMotor:
+		c = cp.NewSimpleMotor(a, b, cs.Rate)
+	default:
+		return nil, fmt.Errorf("ebitencp: unknown constraint type %q", cs.Type)
+	}
+	c.SetMaxForce(cs.MaxForce)
+	c.SetMaxBias(cs.MaxBias)
+	c.SetErrorBias(cs.ErrorBias)
+	c.SetCollideBodies(cs.CollideBodies)
+	return c, nil
+}
+
+// clearSpace removes every constraint, shape and body from space.
+func clearSpace(space *cp.Space) {
+	var constraints []*cp.Constraint
+	var shapes []*cp.Shape
+	var bodies []*cp.Body
+	space.EachConstraint(func(c *cp.Constraint) {
+		constraints = append(constraints, c)
+	})
+	space.EachShape(func(shape *cp.Shape) {
+		shapes = append(shapes, shape)
+	})
+	space.EachBody(func(body *cp.Body) {
+		if body != space.StaticBody {
+			bodies = append(bodies, body)
+		}
+	})
+	for _, c := range constraints {
+		space.RemoveConstraint(c)
+	}
+	for _, shape := range shapes {
+		space.RemoveShape(shape)
+	}
+	for _, body := range bodies {
+		space.RemoveBody(body)
+	}
+}
+
+func vectorOrZero(v *cp.Vector) cp.Vector {
+	if v == nil {
+		return cp.Vector{}
+	}
+	return *v
+}
+
+func vectorPtr(v cp.Vector) *cp.Vector {
+	return &v
+}
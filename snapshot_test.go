@@ -0,0 +1,137 @@
+package ebitencp
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+func addTestBox(space *cp.Space, p cp.Vector) *cp.Body {
+	body := space.AddBody(cp.NewBody(1, cp.MomentForBox(1, 2, 2)))
+	body.SetPosition(p)
+	space.AddShape(cp.NewBox(body, 2, 2, 0))
+	return body
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(space *cp.Space)
+	}{
+		{
+			name:  "empty",
+			build: func(space *cp.Space) {},
+		},
+		{
+			name: "circle in motion",
+			build: func(space *cp.Space) {
+				space.SetGravity(cp.Vector{X: 0, Y: -100})
+				body := space.AddBody(cp.NewBody(1, cp.MomentForCircle(1, 0, 3, cp.Vector{})))
+				body.SetPosition(cp.Vector{X: 10, Y: 20})
+				body.SetAngle(0.5)
+				body.SetVelocity(3, -4)
+				body.SetAngularVelocity(2)
+				shape := space.AddShape(cp.NewCircle(body, 3, cp.Vector{X: 1, Y: 0}))
+				shape.SetFriction(0.7)
+				shape.SetElasticity(0.3)
+			},
+		},
+		{
+			name: "static segment",
+			build: func(space *cp.Space) {
+				space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: -10, Y: 0}, cp.Vector{X: 10, Y: 0}, 1))
+			},
+		},
+		{
+			name: "joints",
+			build: func(space *cp.Space) {
+				a := addTestBox(space, cp.Vector{X: 0, Y: 0})
+				b := addTestBox(space, cp.Vector{X: 5, Y: 0})
+				space.AddConstraint(cp.NewPinJoint(a, b, cp.Vector{}, cp.Vector{}))
+				space.AddConstraint(cp.NewPivotJoint(space.StaticBody, a, cp.Vector{}))
+				space.AddConstraint(cp.NewDampedSpring(a, b, cp.Vector{}, cp.Vector{}, 5, 100, 1))
+				space.AddConstraint(cp.NewGearJoint(a, b, 0.25, 2))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			space := cp.NewSpace()
+			tt.build(space)
+			s, err := TakeSnapshot(space)
+			if err != nil {
+				t.Fatal(err)
+			}
+			restored, err := s.Restore()
+			if err != nil {
+				t.Fatal(err)
+			}
+			again, err := TakeSnapshot(restored)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := json.Marshal(s)
+			got, _ := json.Marshal(again)
+			if string(got) != string(want) {
+				t.Errorf("restored snapshot differs\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestTakeSnapshotConstraintOutsideSpace(t *testing.T) {
+	space := cp.NewSpace()
+	body := addTestBox(space, cp.Vector{})
+	space.AddConstraint(cp.NewPivotJoint(cp.NewKinematicBody(), body, cp.Vector{}))
+	if _, err := TakeSnapshot(space); err == nil {
+		t.Error("TakeSnapshot succeeded with a constraint attached to a body outside the space")
+	}
+}
+
+func TestUnmarshalSpaceVersion(t *testing.T) {
+	if _, err := UnmarshalSpace([]byte(`{"version":0}`)); err == nil {
+		t.Error("UnmarshalSpace accepted an unsupported version")
+	}
+}
+
+func TestSnapshotValidate(t *testing.T) {
+	valid := func() *Snapshot {
+		space := cp.NewSpace()
+		addTestBox(space, cp.Vector{})
+		s, err := TakeSnapshot(space)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	tests := []struct {
+		name    string
+		edit    func(s *Snapshot)
+		wantErr bool
+	}{
+		{name: "valid", edit: func(s *Snapshot) {}},
+		{name: "zero mass", edit: func(s *Snapshot) { s.Bodies[1].Mass = 0 }, wantErr: true},
+		{name: "negative mass", edit: func(s *Snapshot) { s.Bodies[1].Mass = -1 }, wantErr: true},
+		{name: "NaN mass", edit: func(s *Snapshot) { s.Bodies[1].Mass = math.NaN() }, wantErr: true},
+		{name: "zero moment", edit: func(s *Snapshot) { s.Bodies[1].Moment = 0 }, wantErr: true},
+		{name: "NaN moment", edit: func(s *Snapshot) { s.Bodies[1].Moment = math.NaN() }, wantErr: true},
+		{name: "kinematic without mass", edit: func(s *Snapshot) {
+			s.Bodies[1].Type = BodyTypeKinematic
+			s.Bodies[1].Mass = 0
+		}},
+		{name: "two verts", edit: func(s *Snapshot) { s.Shapes[0].Verts = s.Shapes[0].Verts[:2] }, wantErr: true},
+		{name: "no verts", edit: func(s *Snapshot) { s.Shapes[0].Verts = nil }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.edit(s)
+			_, err := s.Restore()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Restore() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
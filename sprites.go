@@ -0,0 +1,180 @@
+package ebitencp
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// Sprite is an image drawn at the transform of a body or a shape.
+// Attach it with SpriteLayer.AttachBody or SpriteLayer.AttachShape.
+type Sprite struct {
+	// Image is drawn as is, so it can be a sub-image of a sprite sheet.
+	Image *ebiten.Image
+	// Anchor is the point of the image placed at the body, as a fraction of its size.
+	// (0.5, 0.5) is the center and (0, 0) the top left corner.
+	Anchor cp.Vector
+	// Scale is the size of one pixel of the image in world units.
+	// A negative X mirrors the image.
+	Scale cp.Vector
+	// Offset moves the anchor in body coordinates.
+	Offset cp.Vector
+	// Angle turns the image in radians on top of the body angle.
+	Angle float64
+	// Sprites are drawn from the lowest Z to the highest,
+	// and in the order they were attached for the same Z.
+	Z          int
+	Hidden     bool
+	ColorScale ebiten.ColorScale
+
+	body  *cp.Body
+	shape *cp.Shape
+}
+
+// NewSprite returns a sprite of img anchored at its center, one world unit per pixel.
+func NewSprite(img *ebiten.Image) *Sprite {
+	return &Sprite{
+		Image:  img,
+		Anchor: cp.Vector{X: 0.5, Y: 0.5},
+		Scale:  cp.Vector{X: 1, Y: 1},
+	}
+}
+
+// Body returns the body the sprite follows.
+func (s *Sprite) Body() *cp.Body {
+	return s.body
+}
+
+// Shape returns the shape the sprite is attached to, or nil when it is attached to a body.
+func (s *Sprite) Shape() *cp.Shape {
+	return s.shape
+}
+
+// SpriteLayer draws images over bodies and shapes. Set it to Drawer.Sprites.
+// DrawSpace then draws the sprites instead of the shapes, and the outlines
+// of the shapes over them when Shapes is true.
+//
+// Sprites keep pointers to their bodies, so detach them when a body is removed.
+// Snapshot.RestoreInto and SimulationControl.Reset replace the bodies of a space,
+// which requires attaching the sprites again.
+type SpriteLayer struct {
+	// Shapes draws the outlines of the shapes over the sprites.
+	Shapes bool
+
+	sprites []*Sprite
+}
+
+func NewSpriteLayer() *SpriteLayer {
+	return &SpriteLayer{}
+}
+
+// AttachBody attaches sprite to body, with Offset from the body position.
+func (l *SpriteLayer) AttachBody(body *cp.Body, sprite *Sprite) *Sprite {
+	sprite.body, sprite.shape = body, nil
+	l.sprites = append(l.sprites, sprite)
+	return sprite
+}
+
+// AttachShape attaches sprite to shape, with Offset from the center of the shape:
+// the center of a circle, the middle of a segment or the centroid of a polygon.
+func (l *SpriteLayer) AttachShape(shape *cp.Shape, sprite *Sprite) *Sprite {
+	sprite.body, sprite.shape = shape.Body(), shape
+	l.sprites = append(l.sprites, sprite)
+	return sprite
+}
+
+// Detach removes sprite from the layer.
+func (l *SpriteLayer) Detach(sprite *Sprite) {
+	l.sprites = slices.DeleteFunc(l.sprites, func(s *Sprite) bool {
+		return s == sprite
+	})
+}
+
+// DetachBody removes the sprites of body and its shapes from the layer.
+func (l *SpriteLayer) DetachBody(body *cp.Body) {
+	l.sprites = slices.DeleteFunc(l.sprites, func(s *Sprite) bool {
+		return s.body == body
+	})
+}
+
+func (l *SpriteLayer) Clear() {
+	l.sprites = nil
+}
+
+// Sprites returns the attached sprites in drawing order.
+func (l *SpriteLayer) Sprites() []*Sprite {
+	l.sort()
+	return l.sprites
+}
+
+func (l *SpriteLayer) sort() {
+	slices.SortStableFunc(l.sprites, func(a, b *Sprite) int {
+		return a.Z - b.Z
+	})
+}
+
+// shapeCenter returns the center of shape in body coordinates.
+func shapeCenter(shape *cp.Shape) cp.Vector {
+	switch class := shape.Class.(type) {
+	case *cp.Circle:
+		return circleOffset(class)
+	case *cp.Segment:
+		return class.A().Lerp(class.B(), 0.5)
+	case *cp.PolyShape:
+		verts := make([]cp.Vector, class.Count())
+		for i := range verts {
+			verts[i] = class.Vert(i)
+		}
+		return cp.CentroidForPoly(len(verts), verts)
+	}
+	return cp.Vector{}
+}
+
+// DrawSprites draws the sprites of Sprites at the transforms blended by Alpha.
+// Sprites of bodies or shapes that are not in a space are skipped.
+// DrawSpace calls it, so call it directly only to draw the sprites without the rest of the space.
+func (d *Drawer) DrawSprites() {
+	if d.Sprites == nil {
+		return
+	}
+	screenGeoM := d.ScreenGeoM()
+	for _, s := range d.Sprites.Sprites() {
+		if s.Hidden || s.Image == nil || s.body == nil {
+			continue
+		}
+		// Bodies and shapes removed from their space are no longer drawn.
+		if bodySpace(s.body) == nil || s.shape != nil && s.shape.Space() == nil {
+			continue
+		}
+		t, angle := d.bodyTransform(s.body)
+		offset := s.Offset
+		if s.shape != nil {
+			offset = offset.Add(shapeCenter(s.shape))
+		}
+		position := t.Point(offset)
+		size := s.Image.Bounds().Size()
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-s.Anchor.X*float64(size.X), -s.Anchor.Y*float64(size.Y))
+		op.GeoM.Scale(s.Scale.X, s.Scale.Y)
+		if !d.FlipYAxis {
+			// Images are y-down and the world is y-up.
+			op.GeoM.Scale(1, -1)
+		}
+		op.GeoM.Rotate(angle + s.Angle)
+		op.GeoM.Translate(position.X, position.Y)
+		op.GeoM.Concat(screenGeoM)
+		op.ColorScale = s.ColorScale
+		op.Filter = ebiten.FilterLinear
+		d.Screen.DrawImage(s.Image, op)
+		d.drawStats.Triangles += 2
+		d.drawStats.DrawCalls++
+	}
+}
+
+// drawShapeOutline draws the outline of shape over its sprite.
+func (d *Drawer) drawShapeOutline(shape *cp.Shape) {
+	t, angle := d.bodyTransform(shape.Body())
+	d.drawShapeAt(shape, t, angle, d.OutlineColor(), cp.FColor{})
+}
@@ -0,0 +1,34 @@
+package ebitencp
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/basicfont"
+)
+
+var defaultFace text.Face = text.NewGoXFace(basicfont.Face7x13)
+
+// DefaultFace returns the font used when none is set.
+func DefaultFace() text.Face {
+	return defaultFace
+}
+
+func lineSpacing(face text.Face) float64 {
+	m := face.Metrics()
+	return m.HAscent + m.HDescent + m.HLineGap
+}
+
+func measureText(str string, face text.Face) (width, height float64) {
+	return text.Measure(str, face, lineSpacing(face))
+}
+
+// drawText draws str with its top left corner at (x, y) in screen coordinates.
+func drawText(screen *ebiten.Image, str string, face text.Face, x, y float64, clr color.Color) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleWithColor(clr)
+	op.LineSpacing = lineSpacing(face)
+	text.Draw(screen, str, face, op)
+}
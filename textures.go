@@ -0,0 +1,195 @@
+package ebitencp
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp/v2"
+)
+
+// TextureWrap is what a Texture shows outside of its image.
+type TextureWrap int
+
+const (
+	// Tile the image
+	TextureRepeat TextureWrap = iota
+	// Show the image once. Outside of it, only the outline of the shape is drawn.
+	TextureClamp
+)
+
+// TextureMapping is how a Texture is sized on a shape.
+type TextureMapping int
+
+const (
+	// One pixel of the image is Scale world units.
+	TextureWorldScale TextureMapping = iota
+	// The image is stretched over the bounding box of the shape in body coordinates.
+	TextureStretch
+)
+
+// Texture fills shapes with an image instead of a solid color.
+// The image is laid out in body coordinates, so it moves and turns with the body.
+type Texture struct {
+	// Image can be a sub-image of a texture atlas.
+	Image   *ebiten.Image
+	Wrap    TextureWrap
+	Mapping TextureMapping
+	// Scale is the size of one pixel of the image in world units with TextureWorldScale.
+	Scale float64
+	// Offset moves the image in body coordinates.
+	Offset cp.Vector
+	// Tint multiplies the image by the fill color of the shape, alpha included.
+	// Otherwise the image is drawn as it is.
+	Tint   bool
+	Filter ebiten.Filter
+}
+
+// NewTexture returns a texture tiling img at one world unit per pixel.
+func NewTexture(img *ebiten.Image) *Texture {
+	return &Texture{
+		Image: img,
+		Scale: 1,
+	}
+}
+
+// TextureFills picks the textures of shapes. Set it to Drawer.Textures.
+// A texture set for a shape takes precedence over the one for its collision type.
+// Shapes without a texture are filled with solid colors.
+type TextureFills struct {
+	shapes         map[*cp.Shape]*Texture
+	collisionTypes map[cp.CollisionType]*Texture
+}
+
+func NewTextureFills() *TextureFills {
+	return &TextureFills{
+		shapes:         map[*cp.Shape]*Texture{},
+		collisionTypes: map[cp.CollisionType]*Texture{},
+	}
+}
+
+// SetShape fills shape with texture. A nil texture removes it.
+func (f *TextureFills) SetShape(shape *cp.Shape, texture *Texture) {
+	if texture == nil {
+		delete(f.shapes, shape)
+		return
+	}
+	f.shapes[shape] = texture
+}
+
+// SetCollisionType fills the shapes of collisionType with texture. A nil texture removes it.
+func (f *TextureFills) SetCollisionType(collisionType cp.CollisionType, texture *Texture) {
+	if texture == nil {
+		delete(f.collisionTypes, collisionType)
+		return
+	}
+	f.collisionTypes[collisionType] = texture
+}
+
+// Texture returns the texture shape is filled with, or nil.
+func (f *TextureFills) Texture(shape *cp.Shape) *Texture {
+	if t, ok := f.shapes[shape]; ok {
+		return t
+	}
+	return f.collisionTypes[shapeCollisionType(shape)]
+}
+
+// texturing is the texture of the shape being drawn, used by drawFill.
+type texturing struct {
+	texture *Texture
+	// toBody converts world coordinates to body coordinates.
+	toBody cp.Transform
+	bounds cp.BB
+}
+
+// beginTexture makes drawFill use the texture of shape until endTexture.
+func (d *Drawer) beginTexture(shape *cp.Shape, t cp.Transform) {
+	if d.Textures == nil {
+		return
+	}
+	texture := d.Textures.Texture(shape)
+	if texture == nil || texture.Image == nil {
+		return
+	}
+	d.texturing = &texturing{texture: texture, toBody: t.Inverse(), bounds: shapeLocalBB(shape)}
+}
+
+func (d *Drawer) endTexture() {
+	d.texturing = nil
+}
+
+// shapeLocalBB returns the bounding box of shape in body coordinates.
+func shapeLocalBB(shape *cp.Shape) cp.BB {
+	switch class := shape.Class.(type) {
+	case *cp.Circle:
+		return cp.NewBBForCircle(circleOffset(class), class.Radius())
+	case *cp.Segment:
+		return cp.NewBBForCircle(class.A(), class.Radius()).Merge(cp.NewBBForCircle(class.B(), class.Radius()))
+	case *cp.PolyShape:
+		bb := cp.BB{L: math.Inf(1), B: math.Inf(1), R: math.Inf(-1), T: math.Inf(-1)}
+		for i := 0; i < class.Count(); i++ {
+			bb = bb.Expand(class.Vert(i))
+		}
+		r := class.Radius()
+		return cp.BB{L: bb.L - r, B: bb.B - r, R: bb.R + r, T: bb.T + r}
+	}
+	return cp.BB{}
+}
+
+// textureSource returns the point of the image of tx at p in body coordinates.
+func (d *Drawer) textureSource(tx *texturing, p cp.Vector) (float32, float32) {
+	t := tx.texture
+	bounds := t.Image.Bounds()
+	p = p.Sub(t.Offset)
+	var u, v float64
+	switch t.Mapping {
+	case TextureStretch:
+		u = (p.X - tx.bounds.L) / (tx.bounds.R - tx.bounds.L) * float64(bounds.Dx())
+		v = (p.Y - tx.bounds.B) / (tx.bounds.T - tx.bounds.B) * float64(bounds.Dy())
+		if !d.FlipYAxis {
+			// Images are y-down and the world is y-up.
+			v = float64(bounds.Dy()) - v
+		}
+	default:
+		scale := t.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+		u, v = p.X/scale, p.Y/scale
+		if !d.FlipYAxis {
+			v = -v
+		}
+	}
+	return float32(float64(bounds.Min.X) + u), float32(float64(bounds.Min.Y) + v)
+}
+
+// drawTexturedFill fills path with the texture being drawn.
+func (d *Drawer) drawTexturedFill(
+	screen *ebiten.Image,
+	path *worldPath,
+	r, g, b, a float32,
+) {
+	tx := d.texturing
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	if !tx.texture.Tint {
+		r, g, b, a = 1, 1, 1, 1
+	}
+	matrix := d.ScreenGeoM()
+	for i := range vs {
+		world := cp.Vector{X: float64(vs[i].DstX) / path.scale, Y: float64(vs[i].DstY) / path.scale}
+		vs[i].SrcX, vs[i].SrcY = d.textureSource(tx, tx.toBody.Point(world))
+		x, y := matrix.Apply(world.X, world.Y)
+		vs[i].DstX, vs[i].DstY = float32(x), float32(y)
+		vs[i].ColorR, vs[i].ColorG, vs[i].ColorB, vs[i].ColorA = r, g, b, a
+	}
+	op := &ebiten.DrawTrianglesOptions{
+		FillRule:  d.OptFill.FillRule,
+		AntiAlias: d.OptFill.AntiAlias,
+		Filter:    tx.texture.Filter,
+		Address:   ebiten.AddressRepeat,
+	}
+	if tx.texture.Wrap == TextureClamp {
+		op.Address = ebiten.AddressClampToZero
+	}
+	screen.DrawTriangles(vs, is, tx.texture.Image, op)
+	d.countDraw(is)
+}
@@ -2,6 +2,7 @@ package ebitencp
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/jakecoffman/cp/v2"
 )
@@ -10,6 +11,32 @@ type Theme struct {
 	Outline                         color.RGBA
 	Shape, ShapeSleeping, ShapeIdle color.RGBA
 	Constraint, CollisionPoint      color.RGBA
+	// Colors of each joint type. Constraint is used for other types and for
+	// the types left zero. DefaultTheme sets them all, so clear them to draw
+	// every joint in Constraint as before.
+	PinJoint, SlideJoint, PivotJoint, GrooveJoint color.RGBA
+	DampedSpring, DampedRotarySpring              color.RGBA
+	RotaryLimitJoint, RatchetJoint, GearJoint     color.RGBA
+	SimpleMotor                                   color.RGBA
+	// Colors of MotionOverlay
+	Velocity, AngularVelocity, CenterOfGravity, Force, Torque color.RGBA
+	// Colors of ContactOverlay
+	ContactNormal, ContactDepth, NormalImpulse, TangentImpulse color.RGBA
+	ContactNew, ContactPersistent                              color.RGBA
+	// Colors of BBOverlay
+	BBDynamic, BBKinematic, BBStatic, BBTree color.RGBA
+	// Color of the links drawn by IslandOverlay
+	IslandLink color.RGBA
+	// Constraint colors from no stress to MaxForce with Drawer.StressColors
+	StressLow, StressHigh color.RGBA
+	// Color of the trails drawn by TrailOverlay
+	Trail color.RGBA
+	// Colors of the queries drawn by Drawer.SegmentQuery and the like
+	Query, QueryHit color.RGBA
+	// Colors of GridOverlay
+	Grid, GridAxisX, GridAxisY, GridLabel color.RGBA
+	// Colors of Minimap
+	MinimapBackground, MinimapFrustum color.RGBA
 }
 
 func toFColor(c color.RGBA) cp.FColor {
@@ -20,13 +47,81 @@ func toFColor(c color.RGBA) cp.FColor {
 	return cp.FColor{R: r, G: g, B: b, A: a}
 }
 
+// colorToFColor converts any color to the non-premultiplied components the drawer uses.
+func colorToFColor(c color.Color) cp.FColor {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return toFColor(color.RGBA(n))
+}
+
 func DefaultTheme() *Theme {
 	return &Theme{
-		Outline:        color.RGBA{0xC8, 0xD2, 0xE6, 0xFF},
-		ShapeSleeping:  color.RGBA{0x33, 0x33, 0x33, 0x80},
-		ShapeIdle:      color.RGBA{0xA8, 0xA8, 0xA8, 0x80},
-		Shape:          color.RGBA{0xB2, 0x4C, 0x99, 0x80},
-		Constraint:     color.RGBA{0x00, 0xBF, 0x00, 255},
-		CollisionPoint: color.RGBA{0xFF, 0x19, 0x33, 255},
+		Outline:            color.RGBA{0xC8, 0xD2, 0xE6, 0xFF},
+		ShapeSleeping:      color.RGBA{0x33, 0x33, 0x33, 0x80},
+		ShapeIdle:          color.RGBA{0xA8, 0xA8, 0xA8, 0x80},
+		Shape:              color.RGBA{0xB2, 0x4C, 0x99, 0x80},
+		Constraint:         color.RGBA{0x00, 0xBF, 0x00, 255},
+		CollisionPoint:     color.RGBA{0xFF, 0x19, 0x33, 255},
+		PinJoint:           color.RGBA{0x00, 0xBF, 0x00, 255},
+		SlideJoint:         color.RGBA{0x66, 0xD9, 0x33, 255},
+		PivotJoint:         color.RGBA{0x00, 0xCC, 0xCC, 255},
+		GrooveJoint:        color.RGBA{0x33, 0x99, 0xFF, 255},
+		DampedSpring:       color.RGBA{0xFF, 0xCC, 0x33, 255},
+		DampedRotarySpring: color.RGBA{0xFF, 0x99, 0x33, 255},
+		RotaryLimitJoint:   color.RGBA{0xCC, 0x66, 0xFF, 255},
+		RatchetJoint:       color.RGBA{0xFF, 0x66, 0xCC, 255},
+		GearJoint:          color.RGBA{0xCC, 0xCC, 0xCC, 255},
+		SimpleMotor:        color.RGBA{0xFF, 0x4C, 0x4C, 255},
+		Velocity:           color.RGBA{0x33, 0xCC, 0xFF, 255},
+		AngularVelocity:    color.RGBA{0x33, 0x99, 0xFF, 255},
+		CenterOfGravity:    color.RGBA{0xFF, 0xFF, 0xFF, 255},
+		Force:              color.RGBA{0xFF, 0xCC, 0x00, 255},
+		Torque:             color.RGBA{0xFF, 0x80, 0x00, 255},
+		ContactNormal:      color.RGBA{0xFF, 0xFF, 0xFF, 255},
+		ContactDepth:       color.RGBA{0xFF, 0x19, 0x33, 255},
+		NormalImpulse:      color.RGBA{0xFF, 0xCC, 0x00, 255},
+		TangentImpulse:     color.RGBA{0x00, 0xE5, 0xCC, 255},
+		ContactNew:         color.RGBA{0xFF, 0xFF, 0x00, 255},
+		ContactPersistent:  color.RGBA{0xFF, 0x19, 0x33, 255},
+		BBDynamic:          color.RGBA{0x4C, 0xB2, 0xFF, 255},
+		BBKinematic:        color.RGBA{0xFF, 0xB2, 0x4C, 255},
+		BBStatic:           color.RGBA{0x80, 0x80, 0x80, 255},
+		BBTree:             color.RGBA{0xFF, 0xFF, 0xFF, 0x40},
+		IslandLink:         color.RGBA{0xFF, 0xFF, 0xFF, 0x80},
+		StressLow:          color.RGBA{0x33, 0x99, 0xFF, 255},
+		StressHigh:         color.RGBA{0xFF, 0x33, 0x19, 255},
+		Trail:              color.RGBA{0xFF, 0xFF, 0xFF, 0xC0},
+		Query:              color.RGBA{0x66, 0xCC, 0xFF, 255},
+		QueryHit:           color.RGBA{0xFF, 0xE5, 0x33, 255},
+		Grid:               color.RGBA{0xFF, 0xFF, 0xFF, 0x20},
+		GridAxisX:          color.RGBA{0xFF, 0x4C, 0x4C, 0xC0},
+		GridAxisY:          color.RGBA{0x4C, 0xFF, 0x4C, 0xC0},
+		GridLabel:          color.RGBA{0xFF, 0xFF, 0xFF, 0xA0},
+		MinimapBackground:  color.RGBA{0x10, 0x10, 0x10, 0xC0},
+		MinimapFrustum:     color.RGBA{0xFF, 0xFF, 0xFF, 0xE0},
+	}
+}
+
+// hsv converts a hue, saturation and value between 0 and 1 to a color.
+func hsv(h, s, v, a float64) cp.FColor {
+	i := math.Floor(h * 6)
+	f := h*6 - i
+	p := v * (1 - s)
+	q := v * (1 - f*s)
+	t := v * (1 - (1-f)*s)
+	var r, g, b float64
+	switch int(i) % 6 {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	case 5:
+		r, g, b = v, p, q
 	}
+	return cp.FColor{R: float32(r), G: float32(g), B: float32(b), A: float32(a)}
 }
@@ -0,0 +1,113 @@
+package ebitencp
+
+import (
+	"github.com/jakecoffman/cp/v2"
+)
+
+// TrailOverlay draws fading trails behind bodies and, optionally, ghosts of
+// their shapes at earlier transforms. Set it to Drawer.Trails.
+//
+// Samples are recorded by Drawer.SaveTransforms, which is called before every
+// step. Runner does this already.
+type TrailOverlay struct {
+	// Length is the number of samples kept for each body.
+	Length int
+	// Interval is the number of steps between samples.
+	Interval int
+	// Ghosts is the number of the latest samples the shapes are drawn at
+	// with decreasing alpha, like onion skin. 0 draws no ghosts.
+	Ghosts int
+	// Filter limits the overlay to the bodies it returns true for.
+	// When it is nil, every dynamic body is traced.
+	Filter func(body *cp.Body) bool
+
+	trails map[*cp.Body]*trail
+	steps  int
+}
+
+type trail struct {
+	samples []bodyTransform
+	seen    bool
+}
+
+func NewTrailOverlay() *TrailOverlay {
+	return &TrailOverlay{
+		Length:   60,
+		Interval: 2,
+	}
+}
+
+// Clear forgets every sample.
+func (o *TrailOverlay) Clear() {
+	clear(o.trails)
+	o.steps = 0
+}
+
+func (o *TrailOverlay) record(space *cp.Space) {
+	o.steps++
+	if o.Interval > 1 && o.steps%o.Interval != 1 {
+		return
+	}
+	if o.trails == nil {
+		o.trails = map[*cp.Body]*trail{}
+	}
+	space.EachBody(func(body *cp.Body) {
+		if o.Filter != nil && !o.Filter(body) || o.Filter == nil && body.GetType() != cp.BODY_DYNAMIC {
+			return
+		}
+		t := o.trails[body]
+		if t == nil {
+			t = &trail{}
+			o.trails[body] = t
+		}
+		t.seen = true
+		t.samples = append(t.samples, bodyTransform{position: body.Position(), angle: body.Angle()})
+		if over := len(t.samples) - o.Length; over > 0 {
+			t.samples = append(t.samples[:0], t.samples[over:]...)
+		}
+	})
+	// Forget bodies that were removed or filtered out.
+	for body, t := range o.trails {
+		if !t.seen {
+			delete(o.trails, body)
+		}
+		t.seen = false
+	}
+}
+
+func (d *Drawer) drawTrails() {
+	o := d.Trails
+	base := toFColor(d.Theme.Trail)
+	for body, t := range o.trails {
+		n := len(t.samples)
+		if n == 0 {
+			continue
+		}
+		current, _ := d.bodyTransform(body)
+		cog := body.CenterOfGravity()
+
+		for i := max(0, n-o.Ghosts); i < n; i++ {
+			s := t.samples[i]
+			fade := float32(i-(n-o.Ghosts)+1) / float32(o.Ghosts+1)
+			st := cp.NewTransformRigid(s.position, s.angle)
+			body.EachShape(func(shape *cp.Shape) {
+				outline, fill := d.OutlineColor(), d.ShapeColor(shape, nil)
+				outline.A *= fade
+				fill.A *= fade
+				d.drawShapeAt(shape, st, s.angle, outline, fill)
+			})
+		}
+
+		prev := cp.NewTransformRigid(t.samples[0].position, t.samples[0].angle).Point(cog)
+		for i := 1; i <= n; i++ {
+			next := current.Point(cog)
+			if i < n {
+				next = cp.NewTransformRigid(t.samples[i].position, t.samples[i].angle).Point(cog)
+			}
+			clr := base
+			clr.A *= float32(i) / float32(n)
+			d.DrawSegment(prev, next, clr, nil)
+			prev = next
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package ebitencp
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/jakecoffman/cp/v2"
+)
+
+// cp keeps some state behind unexported fields without getters.
+// The helpers below read those fields through reflection.
+// They never write; any change goes through cp's own methods.
+
+func unexportedField(ptr interface{}, name string) reflect.Value {
+	return reflect.ValueOf(ptr).Elem().FieldByName(name)
+}
+
+func vectorField(v reflect.Value) cp.Vector {
+	return cp.Vector{X: v.Field(0).Float(), Y: v.Field(1).Float()}
+}
+
+func bodyField(v reflect.Value) *cp.Body {
+	return (*cp.Body)(v.UnsafePointer())
+}
+
+// constraintBodies returns the two bodies a constraint is attached to.
+func constraintBodies(c *cp.Constraint) (a, b *cp.Body) {
+	return bodyField(unexportedField(c, "a")), bodyField(unexportedField(c, "b"))
+}
+
+// bodySpace returns the space body was added to, or nil.
+func bodySpace(body *cp.Body) *cp.Space {
+	return (*cp.Space)(unexportedField(body, "space").UnsafePointer())
+}
+
+// constraintSpace returns the space c was added to, or nil.
+func constraintSpace(c *cp.Constraint) *cp.Space {
+	return (*cp.Space)(unexportedField(c, "space").UnsafePointer())
+}
+
+func constraintCollideBodies(c *cp.Constraint) bool {
+	return unexportedField(c, "collideBodies").Bool()
+}
+
+func shapeCollisionType(s *cp.Shape) cp.CollisionType {
+	return cp.CollisionType(unexportedField(s, "collisionType").Uint())
+}
+
+func shapeSurfaceV(s *cp.Shape) cp.Vector {
+	return vectorField(unexportedField(s, "surfaceV"))
+}
+
+// circleOffset returns the body-local center of a circle.
+func circleOffset(c *cp.Circle) cp.Vector {
+	return vectorField(unexportedField(c, "c"))
+}
+
+func gearJointParams(j *cp.GearJoint) (phase, ratio float64) {
+	return unexportedField(j, "phase").Float(), unexportedField(j, "ratio").Float()
+}
+
+func spaceCollisionSlop(space *cp.Space) float64 {
+	return unexportedField(space, "collisionSlop").Float()
+}
+
+// spaceArbiters returns the arbiters of the last step.
+func spaceArbiters(space *cp.Space) []*cp.Arbiter {
+	return *(*[]*cp.Arbiter)(unsafe.Pointer(unexportedField(space, "arbiters").UnsafeAddr()))
+}
+
+// spaceShapeIndex returns the spatial index of the static or the other shapes of space.
+func spaceShapeIndex(space *cp.Space, static bool) *cp.SpatialIndex {
+	name := "dynamicShapes"
+	if static {
+		name = "staticShapes"
+	}
+	return (*cp.SpatialIndex)(unexportedField(space, name).UnsafePointer())
+}
+
+func spatialIndexer(index *cp.SpatialIndex) cp.SpatialIndexer {
+	return *(*cp.SpatialIndexer)(unsafe.Pointer(unexportedField(index, "class").UnsafeAddr()))
+}
+
+// arbiterImpulses returns the normal (X) and tangent (Y) impulses accumulated
+// by the solver for each contact of arb, relative to its ContactPointSet normal.
+func arbiterImpulses(arb *cp.Arbiter) []cp.Vector {
+	contacts := unexportedField(arb, "contacts")
+	impulses := make([]cp.Vector, arb.Count())
+	for i := range impulses {
+		c := contacts.Index(i)
+		impulses[i] = cp.Vector{X: c.FieldByName("jnAcc").Float(), Y: c.FieldByName("jtAcc").Float()}
+	}
+	return impulses
+}
+
+// treeLeaf returns the leaf of shape in tree, or nil.
+func treeLeaf(tree *cp.BBTree, shape *cp.Shape) *cp.Node {
+	leaves := (*cp.HashSet[*cp.Shape, *cp.Node])(unexportedField(tree, "leaves").UnsafePointer())
+	return leaves.Find(shape.HashId(), shape)
+}
+
+// treeNode returns the box of a BBTree node and either its shape, for leaves, or its children.
+func treeNode(node *cp.Node) (bb cp.BB, shape *cp.Shape, a, b *cp.Node) {
+	v := reflect.ValueOf(node).Elem()
+	box := v.FieldByName("bb")
+	bb = cp.BB{L: box.Field(0).Float(), B: box.Field(1).Float(), R: box.Field(2).Float(), T: box.Field(3).Float()}
+	if node.IsLeaf() {
+		return bb, (*cp.Shape)(v.FieldByName("obj").UnsafePointer()), nil, nil
+	}
+	children := v.FieldByName("Children")
+	a = (*cp.Node)(children.FieldByName("a").UnsafePointer())
+	b = (*cp.Node)(children.FieldByName("b").UnsafePointer())
+	return bb, nil, a, b
+}